@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
+
+// seiban archive [-f file] [-days n | -all]: moves the tasks of the done lists
+// that were done more than n days ago (the "archive" front matter setting by
+// default) to the archive file of the board file
+func runArchive(args []string) {
+	flags := flag.NewFlagSet("archive", flag.ExitOnError)
+	fileName := flags.String("f", "", "board file to archive the done tasks of")
+	days := flags.Int("days", -1, "archive the tasks done more than this many days ago (default: the \"archive\" setting of the board)")
+	all := flags.Bool("all", false, "archive all the done tasks")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	*fileName = boardFileName(*fileName)
+	lock, err := files.LockFile(*fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v, close it to archive tasks\n", err)
+		os.Exit(1)
+	}
+	defer lock.Unlock()
+	data, err := readBoard(*fileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *days < 0 {
+		*days = data.GetConfig().ArchiveAfter
+	}
+	if *all {
+		*days = 0
+	}
+	archived, err := data.Archive(parser.ArchiveBefore(*days))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot archive tasks: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Archived %v tasks to %q\n", len(archived), parser.ArchiveFileName(*fileName))
+}
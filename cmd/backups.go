@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
+
+const backupsUsage = "Usage: seiban backups list [-f file]\n       seiban backups restore <n> [-f file]"
+
+// seiban backups list|restore <n> [-f file]: lists the backups of the board
+// file, the newest first, or replaces the board file with the n-th of them
+func runBackups(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, backupsUsage)
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet("backups "+args[0], flag.ExitOnError)
+	fileName := flags.String("f", "", "board file to list or restore the backups of")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, backupsUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args[1:])
+	positional := flags.Args()
+	if len(positional) > 0 {
+		// the flags may follow the backup number too
+		flags.Parse(positional[1:])
+		positional = slices.Concat(positional[:1], flags.Args())
+	}
+	*fileName = boardFileName(*fileName)
+	switch {
+	case args[0] == "list" && len(positional) == 0:
+		listBackups(*fileName)
+	case args[0] == "restore" && len(positional) == 1:
+		n, err := strconv.Atoi(positional[0])
+		if err != nil || n < 1 {
+			fmt.Fprintf(os.Stderr, "Invalid backup number %q\n", positional[0])
+			os.Exit(2)
+		}
+		restoreBackup(*fileName, n)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+}
+
+// prints the backups of the board file with their numbers
+func listBackups(fileName string) {
+	backups, err := files.ListBackups(fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot list backups of %v: %v\n", fileName, err)
+		os.Exit(1)
+	}
+	if len(backups) == 0 {
+		fmt.Printf("%q has no backups yet\n", fileName)
+		return
+	}
+	for idx, backup := range backups {
+		fmt.Printf("%3d  %v  (%v)\n", idx+1, backup.Time.Format(time.DateTime), backup.Age())
+	}
+}
+
+// replaces the board file with its n-th backup, the board file is backed up
+// first. the board file is not read as a board, so that one with mistakes
+// can be restored too.
+func restoreBackup(fileName string, n int) {
+	lock, err := files.LockFile(fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v, close it to restore a backup\n", err)
+		os.Exit(1)
+	}
+	defer lock.Unlock()
+	backups, err := files.ListBackups(fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot list backups of %v: %v\n", fileName, err)
+		os.Exit(1)
+	}
+	if n > len(backups) {
+		fmt.Fprintf(os.Stderr, "%q has %v backups, see seiban backups list\n", fileName, len(backups))
+		os.Exit(1)
+	}
+	if err := parser.RestoreBackupFile(fileName, backups[n-1]); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot restore backup %v: %v\n", n, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Restored %q from the backup of %v\n", fileName, backups[n-1].Time.Format(time.DateTime))
+}
@@ -1,37 +1,136 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/ppriyankuu/seiban/internals/ui"
 	"github.com/ppriyankuu/seiban/pkg/files"
+	"github.com/ppriyankuu/seiban/pkg/parser"
 )
 
 const defaultFileName = "seiban.md"
 
+// environment variable that names the board file when -f is not given
+const fileEnvVar = "SEIBAN_FILE"
+
 func main() {
-	fileName := flag.String("f", defaultFileName, "markdown file to use as task storage")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "merge":
+			runMerge(os.Args[2:])
+			return
+		case "git-setup":
+			runGitSetup(os.Args[2:])
+			return
+		case "backups":
+			runBackups(os.Args[2:])
+			return
+		case "archive":
+			runArchive(os.Args[2:])
+			return
+		}
+	}
+	fileName := flag.String("f", "", "markdown file to use as task storage (default: $"+fileEnvVar+", or "+defaultFileName+" in the working directory or the closest parent directory that has one)")
+	readOnly := flag.Bool("r", false, "open the board read-only")
+	wait := flag.Bool("w", false, "wait for another seiban to close the board instead of asking")
 	flag.Parse()
-	if !strings.HasSuffix(*fileName, ".md") {
-		log.Fatal("Invalid file extension (make sure it is a .md file)")
+	*fileName = boardFileName(*fileName)
+	if !strings.HasSuffix(*fileName, ".md") && !strings.HasSuffix(*fileName, ".json") {
+		log.Fatal("Invalid file extension (make sure it is a .md or .json file)")
 	}
-	checkFile := files.CheckFile("/" + *fileName)
+	checkFile := files.CheckFile(*fileName)
 	if !checkFile {
 		fmt.Printf("%q doesn't exist. Do you want to create it? (Y[es]/N[o]) ", *fileName)
 		var createFile string
 		fmt.Scanln(&createFile)
 		if createFile == "y" || createFile == "Y" || createFile == "Yes" {
-			files.CreateFile("/" + *fileName)
-			files.WriteInitialContent("/" + *fileName)
+			createBoardFile(*fileName)
 		} else {
 			return
 		}
 	}
-	err := ui.Start("/" + *fileName)
-	if err != nil {
+	if !*readOnly {
+		lock, err := files.LockFile(*fileName)
+		var lockedError *files.LockedError
+		if errors.As(err, &lockedError) {
+			answer := ""
+			if *wait {
+				answer = "w"
+			} else {
+				fmt.Printf("%v. Open it read-only or wait for it to be closed? (R[ead-only]/W[ait]/Q[uit]) ", lockedError)
+				fmt.Scanln(&answer)
+			}
+			switch strings.ToLower(answer) {
+			case "r", "read-only":
+				*readOnly = true
+			case "w", "wait":
+				fmt.Printf("Waiting for %q to be closed...\n", *fileName)
+				lock, err = files.WaitLockFile(*fileName)
+			default:
+				return
+			}
+		}
+		if err != nil && !*readOnly {
+			log.Fatal(err)
+		}
+		if lock != nil {
+			defer lock.Unlock()
+		}
+	}
+	printWarnings(*fileName)
+	if err := ui.Start(*fileName, *readOnly); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// prints the lines of the board file that look like a mistake to stderr, one
+// per line as "file:line:column: warning: message", so editors can jump to them
+func printWarnings(fileName string) {
+	data := &parser.Data{}
+	data.SetFileName(fileName)
+	if err := data.Load(); err != nil {
+		log.Fatal(err)
+	}
+	if warnings := data.Warnings(); len(warnings) > 0 {
+		fmt.Fprintln(os.Stderr, warnings)
+	}
+}
+
+// returns the board file to use: the one given with -f, else the one named by
+// SEIBAN_FILE, else the closest seiban.md found walking up from the working
+// directory, else seiban.md in the working directory
+func boardFileName(flagFileName string) string {
+	if len(flagFileName) > 0 {
+		return flagFileName
+	}
+	if envFileName := os.Getenv(fileEnvVar); len(envFileName) > 0 {
+		return envFileName
+	}
+	if foundFileName, found := files.FindFile(defaultFileName); found {
+		return foundFileName
+	}
+	return defaultFileName
+}
+
+// creates a board file with the initial lists, in JSON if its name ends in ".json"
+func createBoardFile(fileName string) {
+	if !strings.HasSuffix(fileName, ".json") {
+		files.CreateFile(fileName)
+		files.WriteInitialContent(fileName)
+		return
+	}
+	data := &parser.Data{}
+	data.SetStorage(parser.NewMemoryStorage(strings.Split(files.InitialContent(fileName), "\n")))
+	if err := data.Load(); err != nil {
+		log.Fatal(err)
+	}
+	data.SetFileName(fileName)
+	if err := data.Save(); err != nil {
 		log.Fatal(err)
 	}
 }
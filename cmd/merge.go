@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
+
+// name of the merge driver in the git config
+const mergeDriverName = "seiban"
+
+// seiban merge %O %A %B: merges the board files %A (ours) and %B (theirs),
+// both changed from %O, task by task and writes the result to %A. exits with
+// 1 if there are conflicts: tasks changed on both sides are marked in the
+// file, changes to boards and lists that cannot be merged are printed.
+func runMerge(args []string) {
+	if len(args) != 3 {
+		fmt.Fprintln(os.Stderr, "Usage: seiban merge <base> <ours> <theirs>")
+		os.Exit(2)
+	}
+	var versions []*parser.Data
+	for _, fileName := range args {
+		data, err := readBoard(fileName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		versions = append(versions, data)
+	}
+	conflicts, err := parser.MergeFiles(versions[0], versions[1], versions[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	taskConflicts := 0
+	for _, conflict := range conflicts {
+		if len(conflict.Structure) == 0 {
+			taskConflicts++
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "seiban: cannot merge the changes to %v: ours %v, theirs %v\n", conflict.Structure, conflict.OursChange, conflict.TheirsChange)
+	}
+	if taskConflicts > 0 {
+		fmt.Fprintf(os.Stderr, "seiban: %v tasks changed on both sides, marked with conflict markers\n", taskConflicts)
+	}
+	if len(conflicts) > 0 {
+		os.Exit(1)
+	}
+}
+
+// reads and parses a board file given on the command line
+func readBoard(fileName string) (*parser.Data, error) {
+	data := &parser.Data{}
+	data.SetFileName(fileName)
+	if err := data.Load(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// seiban git-setup [-f file]: makes git merge the board file with seiban merge,
+// by adding it to the .gitattributes next to it and the merge driver to the
+// git config of its repository
+func runGitSetup(args []string) {
+	flags := flag.NewFlagSet("git-setup", flag.ExitOnError)
+	fileName := flags.String("f", "", "board file git merges with seiban merge")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	*fileName = boardFileName(*fileName)
+	boardDir := filepath.Dir(*fileName)
+	attribute := fmt.Sprintf("%v merge=%v", filepath.Base(*fileName), mergeDriverName)
+	if err := addGitAttribute(boardDir, attribute); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot update .gitattributes: %v\n", err)
+		os.Exit(1)
+	}
+	command := "seiban"
+	if _, err := exec.LookPath(command); err != nil {
+		// not installed, using this binary
+		if command, err = os.Executable(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	config := [][]string{
+		{"merge." + mergeDriverName + ".name", "seiban board merge"},
+		{"merge." + mergeDriverName + ".driver", command + " merge %O %A %B"},
+	}
+	for _, entry := range config {
+		gitConfig := exec.Command("git", "config", entry[0], entry[1])
+		gitConfig.Dir = boardDir
+		if output, err := gitConfig.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot set git config %v: %v\n%s", entry[0], err, output)
+			os.Exit(1)
+		}
+	}
+	fmt.Printf("git now merges %q with %q\n", *fileName, config[1][1])
+}
+
+// adds a line to the .gitattributes file of the directory, unless it is there already
+func addGitAttribute(dir, attribute string) error {
+	attributesPath := filepath.Join(dir, ".gitattributes")
+	content, err := os.ReadFile(attributesPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if slices.Contains(lines, attribute) {
+		return nil
+	}
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, attribute+"\n"...)
+	return os.WriteFile(attributesPath, content, 0644)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	command "github.com/ppriyankuu/seiban/pkg/commands"
+	"github.com/ppriyankuu/seiban/pkg/parser"
 	"github.com/rivo/tview"
 )
 
@@ -13,7 +14,8 @@ func NewAddPage(p *BoardPage, pos int) tview.Primitive {
 	width, height := GetSize()
 	form := tview.NewForm().
 		AddInputField("Task", "", width/4, nil, nil).
-		AddInputField("Task Description", "", width/4, nil, nil)
+		AddTextArea("Task Description", "", width/4, 5, 0, nil)
+	form = addMetadataFields(form, parser.ListItem{Tags: p.data.GetConfig().DefaultTags}, width/4)
 	form.SetFieldBackgroundColor(tcell.ColorWheat)  // Set background color of input fields
 	form.SetFieldTextColor(tcell.ColorBlack)        // Set text color for better contrast
 	form.SetButtonBackgroundColor(tcell.ColorWheat) // Set button background color
@@ -26,9 +28,16 @@ func NewAddPage(p *BoardPage, pos int) tview.Primitive {
 		if len(taskName) <= 0 {
 			return
 		}
-		taskDesc := form.GetFormItemByLabel("Task Description").(*tview.InputField).GetText()
-		taskDesc = strings.TrimSpace(taskDesc)
-		addTaskCommand := command.CreateAddTaskCommand(p.activeListIdx, taskName, taskDesc, pos)
+		taskDesc := form.GetFormItemByLabel("Task Description").(*tview.TextArea).GetText()
+		taskDesc = trimDescription(taskDesc)
+		task := parser.ListItem{
+			ItemName:        taskName,
+			ItemDescription: taskDesc,
+		}
+		if !readMetadataFields(form, &task) {
+			return
+		}
+		addTaskCommand := command.CreateAddTaskCommand(p.activeListIdx, task, pos)
 		if err := p.command.Execute(addTaskCommand); err != nil {
 			app.Stop()
 			log.Fatal(err)
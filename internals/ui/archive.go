@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	command "github.com/ppriyankuu/seiban/pkg/commands"
+	"github.com/ppriyankuu/seiban/pkg/parser"
+	"github.com/rivo/tview"
+)
+
+// asks to move the done tasks to the archive file, the ones done more than
+// "archive" days ago or all of them if the front matter does not set it
+func (p *BoardPage) archiveTasks() {
+	days := p.data.GetConfig().ArchiveAfter
+	text := "Move all the tasks of the done lists to the archive?"
+	if days > 0 {
+		text = fmt.Sprintf("Move the tasks of the done lists done more than %v days ago to the archive?", days)
+	}
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"Archive", "Cancel"}).
+		SetDoneFunc(func(_ int, buttonLabel string) {
+			pages.RemovePage("archive-tasks")
+			if buttonLabel != "Archive" {
+				return
+			}
+			doneBefore := parser.ArchiveBefore(days)
+			archivedCount := p.data.CountArchivable(doneBefore)
+			if archivedCount == 0 || !p.executeFileCommand(command.CreateArchiveCommand(doneBefore)) {
+				return
+			}
+			pages.AddPage("message", NewMessagePage(fmt.Sprintf("Moved %v tasks to %v", archivedCount, parser.ArchiveFileName(p.data.GetFileName()))), true, true)
+		})
+	styleModal(modal)
+	pages.AddPage("archive-tasks", modal, true, true)
+}
+
+func (p *BoardPage) openArchive() {
+	archive, err := p.data.OpenArchive()
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	if len(archive.ArchivedTasks()) == 0 {
+		pages.AddPage("message", NewMessagePage("The archive is empty."), true, true)
+		return
+	}
+	pages.AddPage("archive", NewArchivePage(p, archive), true, true)
+}
+
+// displays the tasks of the archive, the newest first. the selected task is
+// moved back to the done list of its board once confirmed.
+func NewArchivePage(p *BoardPage, archive *parser.Data) tview.Primitive {
+	archivedTasks := archive.ArchivedTasks()
+	showBoardNames := archive.GetBoardCount() > 1
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, archivedTask := range archivedTasks {
+		text := archivedTask.ArchivedOn + "  " + formatTask(archivedTask.Task)
+		if showBoardNames {
+			text = archivedTask.ArchivedOn + "  " + tview.Escape(archivedTask.BoardName) + " › " + formatTask(archivedTask.Task)
+		}
+		list.AddItem(text, "", 0, nil)
+	}
+	list.SetSelectedFunc(func(idx int, _, _ string, _ rune) {
+		if p.data.IsReadOnly() {
+			return
+		}
+		pages.AddPage("unarchive", NewUnarchivePage(p, archivedTasks[idx]), true, true)
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closeArchivePage()
+			return nil
+		}
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case 'q':
+			closeArchivePage()
+			return nil
+		}
+		return event
+	})
+	list.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitle("Archive (Enter: put back on the board)").
+		SetTitleAlign(tview.AlignCenter)
+	width, height := GetSize()
+	return GetCenteredModal(list, width*3/4, height*3/4)
+}
+
+// asks to move an archived task back to the done list of its board
+func NewUnarchivePage(p *BoardPage, archivedTask parser.ArchivedTask) tview.Primitive {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Put %q back in the done list of %v?", archivedTask.Task.ItemName, strings.TrimSpace(archivedTask.BoardName))).
+		AddButtons([]string{"Put back", "Cancel"}).
+		SetDoneFunc(func(_ int, buttonLabel string) {
+			pages.RemovePage("unarchive")
+			if buttonLabel != "Put back" {
+				return
+			}
+			closeArchivePage()
+			p.executeFileCommand(command.CreateUnarchiveTaskCommand(archivedTask))
+		})
+	styleModal(modal)
+	return modal
+}
+
+func closeArchivePage() {
+	pages.RemovePage("archive")
+	pages.SwitchToPage("board")
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
+
+// remembers a change made with a command for the message of the next commit
+// of the board file, and commits once "autocommit" changes are waiting
+func (p *BoardPage) commandDone(description string) {
+	config := p.data.GetConfig()
+	if !config.AutoCommit || p.data.IsReadOnly() || len(description) == 0 {
+		return
+	}
+	p.changes = append(p.changes, description)
+	if config.AutoCommitEvery == 0 || len(p.changes) < config.AutoCommitEvery {
+		return
+	}
+	if err := p.autoCommit(); err != nil {
+		pages.AddPage("message", NewMessagePage(fmt.Sprintf("Cannot commit the board file:\n%v", err)), true, true)
+	}
+}
+
+// commits the board file and its archive to git with the changes made since
+// the last commit as the message, if "autocommit" is set in the front matter
+func (p *BoardPage) autoCommit() error {
+	if !p.data.GetConfig().AutoCommit || p.data.IsReadOnly() || len(p.changes) == 0 {
+		return nil
+	}
+	fileNames := []string{p.data.GetFileName()}
+	if archiveFileName := parser.ArchiveFileName(p.data.GetFileName()); files.CheckFile(archiveFileName) {
+		fileNames = append(fileNames, archiveFileName)
+	}
+	if _, err := files.GitCommitFiles(commitMessage(p.changes), fileNames...); err != nil {
+		return err
+	}
+	p.changes = nil
+	return nil
+}
+
+// returns the message of a commit of the changes, e.g.
+// "seiban: move 'fix login' TODO → DOING". the first change is the subject,
+// with several changes all of them are listed in the body.
+func commitMessage(changes []string) string {
+	if len(changes) == 1 {
+		return "seiban: " + changes[0]
+	}
+	return fmt.Sprintf("seiban: %v and %v more\n\n- %v", changes[0], len(changes)-1, strings.Join(changes, "\n- "))
+}
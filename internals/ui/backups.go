@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	command "github.com/ppriyankuu/seiban/pkg/commands"
+	"github.com/ppriyankuu/seiban/pkg/files"
+	"github.com/rivo/tview"
+)
+
+func (p *BoardPage) openBackups() {
+	backups, err := p.data.GetBackups()
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	if len(backups) == 0 {
+		pages.AddPage("message", NewMessagePage("The board file has no backups yet, they are written when the board is saved."), true, true)
+		return
+	}
+	pages.AddPage("backups", NewBackupsPage(p, backups), true, true)
+}
+
+// displays the backups of the board file, the newest first, with the content of
+// the selected one. the selected backup replaces the board file once confirmed.
+func NewBackupsPage(p *BoardPage, backups []files.Backup) tview.Primitive {
+	list := tview.NewList().ShowSecondaryText(false)
+	preview := tview.NewTextView().SetDynamicColors(true)
+	preview.SetBorder(true).SetBorderColor(theme.BorderColor)
+	showPreview := func(idx int) {
+		fileContent, err := files.ReadFile(backups[idx].Path)
+		if err != nil {
+			preview.SetText(tview.Escape(err.Error()))
+			return
+		}
+		preview.SetText(tview.Escape(strings.Join(fileContent, "\n"))).ScrollToBeginning()
+	}
+	for _, backup := range backups {
+		list.AddItem(fmt.Sprintf("%v (%v)", backup.Time.Format(time.DateTime), backup.Age()), "", 0, nil)
+	}
+	list.SetChangedFunc(func(idx int, _, _ string, _ rune) {
+		showPreview(idx)
+	})
+	list.SetSelectedFunc(func(idx int, _, _ string, _ rune) {
+		if p.data.IsReadOnly() {
+			return
+		}
+		pages.AddPage("restore-backup", NewRestoreBackupPage(p, backups[idx]), true, true)
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closeBackupsPage()
+			return nil
+		}
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case 'q':
+			closeBackupsPage()
+			return nil
+		}
+		return event
+	})
+	showPreview(0)
+
+	layout := tview.NewFlex().
+		AddItem(list, 0, 1, true).
+		AddItem(preview, 0, 2, false)
+	layout.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitle("Backups").
+		SetTitleAlign(tview.AlignCenter)
+	width, height := GetSize()
+	return GetCenteredModal(layout, width*3/4, height*3/4)
+}
+
+// asks to replace the board file with the backup
+func NewRestoreBackupPage(p *BoardPage, backup files.Backup) tview.Primitive {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Restore the board from the backup of %v?\nThe board as it is now is backed up first, and the restore can be undone.", backup.Time.Format(time.DateTime))).
+		AddButtons([]string{"Restore", "Cancel"}).
+		SetDoneFunc(func(_ int, buttonLabel string) {
+			pages.RemovePage("restore-backup")
+			if buttonLabel != "Restore" {
+				return
+			}
+			closeBackupsPage()
+			fileContent, err := files.ReadFile(backup.Path)
+			if err == nil {
+				err = files.WriteBackup(p.data.GetFileName(), p.data.Content())
+			}
+			if err != nil {
+				pages.AddPage("message", NewMessagePage(fmt.Sprintf("Cannot restore the backup: %v", err)), true, true)
+				return
+			}
+			p.executeFileCommand(command.CreateRestoreBackupCommand(fileContent, backup.Time))
+		})
+	styleModal(modal)
+	return modal
+}
+
+func closeBackupsPage() {
+	pages.RemovePage("backups")
+	pages.SwitchToPage("board")
+}
@@ -18,26 +18,149 @@ type BoardPage struct {
 	command        *command.CommandManager
 	activeListIdx  int
 	activeTaskIdxs []int
+	// index of the board that is shown
+	boardIdx int
+	// key of every action and the action of every key, see keys.go
+	keys    map[string]rune
+	actions map[rune]string
+	// changes made since the board file was last committed, see autocommit.go
+	changes []string
+	// the frame around the lists, with the board tabs and the footer
+	frame *tview.Frame
+	// what the last undo or redo did, shown above the footer
+	status string
 }
 
-func NewBoardPage(fileName string) *BoardPage {
-	theme := defaultTheme()
+// reads the board file, see parser.Data.Warnings for its lines that look like a mistake.
+// a read-only board can be browsed but not changed.
+func NewBoardPage(fileName string, readOnly bool) (*BoardPage, error) {
 	data := &parser.Data{}
 	data.SetFileName(fileName)
-	fileContent := data.GetContentFromFile()
-	if err := data.ParseData(fileContent); err != nil {
-		log.Fatal(err)
+	data.SetReadOnly(readOnly)
+	if err := data.Load(); err != nil {
+		return nil, err
 	}
-	command := command.CreateNewCommand(data)
+	// starting on the first board that has lists
+	for boardIdx := range data.GetBoardCount() {
+		listNames, err := data.GetBoardListNames(boardIdx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(listNames) > 0 {
+			data.SetActiveBoard(boardIdx)
+			break
+		}
+	}
+	config := data.GetConfig()
+	theme := themeFromConfig(config.Theme)
+	keys := actionKeys(config.Keys)
 	listCount := len(data.GetListNames())
-	return &BoardPage{
+	p := &BoardPage{
 		lists:          make([]*tview.List, listCount),
 		data:           data,
-		command:        command,
 		theme:          theme,
 		activeListIdx:  0,
 		activeTaskIdxs: make([]int, listCount),
+		boardIdx:       data.GetActiveBoardIdx(),
+		keys:           keys,
+		actions:        keymap(keys),
+	}
+	p.command = p.newCommandManager()
+	return p, nil
+}
+
+// returns a command manager for the data, with the history of the journal
+// of the board file if it was written for the board as it is now
+func (p *BoardPage) newCommandManager() *command.CommandManager {
+	commandManager := command.CreateNewCommand(p.data)
+	commandManager.OnChange(p.changed)
+	commandManager.OnJournalError(func(err error) {
+		pages.AddPage("message", NewMessagePage(fmt.Sprintf("The undo history cannot be written, it is kept until seiban quits:\n%v", err)), true, true)
+	})
+	if !p.data.IsReadOnly() {
+		// undo and redo work across sessions
+		if err := commandManager.UseJournal(p.data.GetFileName()); err != nil {
+			app.Stop()
+			log.Fatal(err)
+		}
+	}
+	return commandManager
+}
+
+// called with a description of every command executed, undone or redone.
+// the board file is saved once for each of them.
+func (p *BoardPage) changed(description string) {
+	p.save()
+	// set again by undo and redo once they are done
+	p.setStatus("")
+	p.commandDone(description)
+}
+
+// returns the text shown under the board
+func (p *BoardPage) footer() string {
+	footer := fmt.Sprintf("%v: help \t %v:quit", keyName(p.keys, "help"), keyName(p.keys, "quit"))
+	if p.data.IsReadOnly() {
+		footer = "[read-only] \t " + footer
+	}
+	return footer
+}
+
+// writes the board file, the app is stopped if it cannot be written. a file
+// changed on disk since it was last read or written is merged first, see
+// parser.SaveMerged. reports false if the board is not saved yet, e.g. because
+// tasks changed on both sides wait for the user to pick a version, it is
+// saved once they are all picked.
+func (p *BoardPage) save() bool {
+	if p.data.IsReadOnly() {
+		return true
+	}
+	if pages.HasPage("conflict") {
+		return false
+	}
+	if changed, err := p.data.FileChanged(); err == nil && changed {
+		var conflicts []parser.MergeConflict
+		merged := p.updateFromFile(func() (bool, error) {
+			var err error
+			conflicts, err = p.data.SaveMerged()
+			return true, err
+		})
+		if !merged {
+			return false
+		}
+		if len(conflicts) > 0 {
+			pages.AddPage("conflict", NewConflictPage(p, conflicts), true, true)
+			return false
+		}
+		return true
+	}
+	if !p.data.HasUnsavedChanges() {
+		return true
+	}
+	if err := p.data.Save(); err != nil {
+		app.Stop()
+		log.Fatal(err)
 	}
+	return true
+}
+
+// executes a command that also reads or writes files other than the board
+// file (e.g. the archive or a backup), it can fail without stopping the app.
+// reports false if it failed.
+func (p *BoardPage) executeFileCommand(fileCommand command.Command) bool {
+	// the command saves the board itself, changes made on disk are merged first
+	if !p.save() {
+		return false
+	}
+	if err := p.command.Execute(fileCommand); err != nil {
+		pages.AddPage("message", NewMessagePage(fmt.Sprintf("The board cannot be updated:\n%v", err)), true, true)
+		return false
+	}
+	// the tasks they show may be gone
+	for _, name := range taskPages {
+		pages.RemovePage(name)
+	}
+	p.redrawBoard()
+	return true
 }
 
 func (p *BoardPage) Page() tview.Primitive {
@@ -47,18 +170,12 @@ func (p *BoardPage) Page() tview.Primitive {
 		log.Fatal("Error: No lists found in data.")
 	}
 	for i := range listNames {
-		curListTaskCount, err := p.data.GetTaskCount(i)
-		if err != nil {
-			app.Stop()
-			log.Fatal(err)
-		}
-		listName := fmt.Sprintf("%s [%d]", listNames[i], curListTaskCount)
 		p.lists[i] = tview.NewList()
 		p.lists[i].
 			ShowSecondaryText(false).
 			SetBorder(true)
 		p.lists[i].SetBorderColor(theme.PrimitiveBackgroundColor)
-		p.lists[i].SetTitle(listName)
+		p.lists[i].SetTitle(p.listTitle(i))
 		p.setInputCapture(i)
 		p.addTasksToList(i)
 		flex.AddItem(p.lists[i], 0, 1, i == 0)
@@ -67,13 +184,26 @@ func (p *BoardPage) Page() tview.Primitive {
 	if len(p.lists) > 0 {
 		p.lists[0].SetBorderColor(theme.ContrastBackgroundColor)
 	}
-	boardName := p.data.GetBoardName()
-	boardName = "Board: " + boardName
-	frame := tview.NewFrame(flex).
-		SetBorders(0, 0, 1, 0, 1, 1).
-		AddText(boardName, true, tview.AlignCenter, p.theme.TitleColor).
-		AddText("?: help \t q:quit", false, tview.AlignCenter, p.theme.PrimaryTextColor)
-	return frame
+	p.frame = tview.NewFrame(flex).
+		SetBorders(0, 0, 1, 0, 1, 1)
+	p.setFrameTexts()
+	return p.frame
+}
+
+// writes the board tabs, the status and the footer on the frame
+func (p *BoardPage) setFrameTexts() {
+	p.frame.Clear().
+		AddText(p.boardTabs(), true, tview.AlignCenter, p.theme.TitleColor).
+		AddText(p.footer(), false, tview.AlignCenter, p.theme.PrimaryTextColor).
+		AddText(tview.Escape(p.status), false, tview.AlignCenter, tcell.ColorWheat)
+}
+
+// shows what the last undo or redo did, e.g. "Undid: move 'deploy' TODO → DOING"
+func (p *BoardPage) setStatus(status string) {
+	p.status = status
+	if p.frame != nil {
+		p.setFrameTexts()
+	}
 }
 
 func (p *BoardPage) up() {
@@ -121,24 +251,51 @@ func (p *BoardPage) left() {
 
 func (p *BoardPage) redraw(listIdx int) {
 	p.lists[listIdx].Clear()
-	tasks, err := p.data.GetTasks(listIdx)
+	tasks, err := p.data.GetListItems(listIdx)
 	if err != nil {
 		app.Stop()
 		log.Fatal(err)
 	}
 	for _, item := range tasks {
-		p.lists[listIdx].AddItem(item, "", 0, nil)
+		p.lists[listIdx].AddItem(formatTask(item), "", 0, nil)
 	}
+	p.lists[listIdx].SetTitle(p.listTitle(listIdx))
+	activeListIdx := p.activeListIdx
+	p.lists[activeListIdx].SetCurrentItem(p.activeTaskIdxs[activeListIdx])
+}
+
+// returns the title of a list with its task count, and its wip limit if it has one.
+// the title turns red once the list holds more tasks than its limit.
+func (p *BoardPage) listTitle(listIdx int) string {
 	listNames := p.data.GetListNames()
 	curListTaskCount, err := p.data.GetTaskCount(listIdx)
 	if err != nil {
 		app.Stop()
 		log.Fatal(err)
 	}
-	listName := fmt.Sprintf("%s [%d]", listNames[listIdx], curListTaskCount)
-	p.lists[listIdx].SetTitle(listName)
-	activeListIdx := p.activeListIdx
-	p.lists[activeListIdx].SetCurrentItem(p.activeTaskIdxs[activeListIdx])
+	listName := tview.Escape(listNames[listIdx])
+	limit, ok := p.data.GetWIPLimit(listIdx)
+	if !ok {
+		return fmt.Sprintf("%s [%d]", listName, curListTaskCount)
+	}
+	if curListTaskCount > limit {
+		return fmt.Sprintf("[red]%s [%d/%d][-]", listName, curListTaskCount, limit)
+	}
+	return fmt.Sprintf("%s [%d/%d]", listName, curListTaskCount, limit)
+}
+
+// reports whether a list has reached its wip limit, tasks are not moved into full lists
+func (p *BoardPage) listFull(listIdx int) bool {
+	limit, ok := p.data.GetWIPLimit(listIdx)
+	if !ok {
+		return false
+	}
+	taskCount, err := p.data.GetTaskCount(listIdx)
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	return taskCount >= limit
 }
 
 func (p *BoardPage) redrawAll() {
@@ -149,15 +306,30 @@ func (p *BoardPage) redrawAll() {
 }
 
 func (p *BoardPage) redo() {
+	future := p.command.Future()
+	if len(future) == 0 {
+		return
+	}
 	if err := p.command.Redo(); err != nil {
+		p.historyFailed(err)
+		return
+	}
+	p.redrawBoard()
+	p.setStatus("Redid: " + future[0].Description)
+}
+
+// returns the id of a task on the board
+func (p *BoardPage) taskID(listIdx, taskIdx int) string {
+	task, err := p.data.GetTask(listIdx, taskIdx)
+	if err != nil {
 		app.Stop()
 		log.Fatal(err)
 	}
-	p.redrawAll()
+	return task.ID
 }
 
 func (p *BoardPage) swapListItem(listIdx, taskIdxFirst, taskIdxSecond int) {
-	swapListItemCommand := command.CreateSwapListItemCommand(listIdx, taskIdxFirst, taskIdxSecond)
+	swapListItemCommand := command.CreateSwapListItemCommand(p.taskID(listIdx, taskIdxFirst), p.taskID(listIdx, taskIdxSecond))
 	if err := p.command.Execute(swapListItemCommand); err != nil {
 		app.Stop()
 		log.Fatal(err)
@@ -176,7 +348,6 @@ func (p *BoardPage) moveDown() {
 		return
 	}
 	p.swapListItem(activeListIdx, activeTaskIdx, activeTaskIdx+1)
-	p.data.Save()
 	p.redraw(p.activeListIdx)
 	p.down()
 }
@@ -188,14 +359,37 @@ func (p *BoardPage) moveUp() {
 		return
 	}
 	p.swapListItem(activeListIdx, activeTaskIdx, activeTaskIdx-1)
-	p.data.Save()
 	p.redraw(p.activeListIdx)
 	p.up()
 }
 
+// moves a task to another list. a task moved into the done list is marked
+// as done and one moved out of it as not done, in the same transaction so
+// that one undo takes back both.
 func (p *BoardPage) moveTask(prevTaskIdx, prevListIdx, newListIdx int) {
-	moveTaskCommand := command.CreateMoveTaskCommand(prevTaskIdx, prevListIdx, newListIdx)
-	if err := p.command.Execute(moveTaskCommand); err != nil {
+	task, err := p.data.GetTask(prevListIdx, prevTaskIdx)
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	taskID, wasDone := task.ID, task.Done
+	if err := p.command.Begin(); err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	// a failing step rolls back the transaction
+	if err := p.command.Execute(command.CreateMoveTaskCommand(taskID, newListIdx)); err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	doneListIdx := p.data.GetDoneListIdx()
+	if done := newListIdx == doneListIdx; done != wasDone && (done || prevListIdx == doneListIdx) {
+		if err := p.command.Execute(command.CreateSetTaskDoneCommand(taskID, done)); err != nil {
+			app.Stop()
+			log.Fatal(err)
+		}
+	}
+	if err := p.command.Commit(); err != nil {
 		app.Stop()
 		log.Fatal(err)
 	}
@@ -214,7 +408,7 @@ func (p *BoardPage) fixActiveTaskIdx() error {
 
 func (p *BoardPage) moveLeft() {
 	activeListIdx := p.activeListIdx
-	if activeListIdx == 0 {
+	if activeListIdx == 0 || p.listFull(activeListIdx-1) {
 		return
 	}
 	taskCount, err := p.data.GetTaskCount(activeListIdx)
@@ -226,7 +420,6 @@ func (p *BoardPage) moveLeft() {
 		return
 	}
 	p.moveTask(p.activeTaskIdxs[activeListIdx], activeListIdx, activeListIdx-1)
-	p.data.Save()
 	if err := p.fixActiveTaskIdx(); err != nil {
 		app.Stop()
 		log.Fatal(err)
@@ -247,7 +440,7 @@ func (p *BoardPage) moveLeft() {
 func (p *BoardPage) moveRight() {
 	activeListIdx := p.activeListIdx
 	listCount := len(p.lists)
-	if activeListIdx+1 >= listCount {
+	if activeListIdx+1 >= listCount || p.listFull(activeListIdx+1) {
 		return
 	}
 	taskCount, err := p.data.GetTaskCount(activeListIdx)
@@ -259,7 +452,6 @@ func (p *BoardPage) moveRight() {
 		return
 	}
 	p.moveTask(p.activeTaskIdxs[activeListIdx], activeListIdx, activeListIdx+1)
-	p.data.Save()
 	p.redraw(p.activeListIdx)
 	if err := p.fixActiveTaskIdx(); err != nil {
 		app.Stop()
@@ -306,12 +498,11 @@ func (p *BoardPage) removeTask() {
 		return
 	}
 	removeTaskIdx := p.activeTaskIdxs[activeListIdx]
-	removeTaskCommand := command.CreateRemoveTaskCommand(activeListIdx, removeTaskIdx)
+	removeTaskCommand := command.CreateRemoveTaskCommand(p.taskID(activeListIdx, removeTaskIdx))
 	if err := p.command.Execute(removeTaskCommand); err != nil {
 		app.Stop()
 		log.Fatal(err)
 	}
-	p.data.Save()
 	p.up()
 	p.redraw(activeListIdx)
 }
@@ -319,9 +510,8 @@ func (p *BoardPage) removeTask() {
 func (p *BoardPage) taskCompleted() {
 	activeListIdx := p.activeListIdx
 	activeTaskIdx := p.activeTaskIdxs[activeListIdx]
-	listCount := p.data.GetListCount()
-	taskDoneIdx := listCount - 1
-	if activeListIdx == taskDoneIdx {
+	taskDoneIdx := p.data.GetDoneListIdx()
+	if activeListIdx == taskDoneIdx || p.listFull(taskDoneIdx) {
 		return
 	}
 	taskCount, err := p.data.GetTaskCount(activeListIdx)
@@ -332,7 +522,11 @@ func (p *BoardPage) taskCompleted() {
 	if taskCount <= 0 {
 		return
 	}
-	p.moveTask(activeTaskIdx, activeListIdx, taskDoneIdx)
+	completeTaskCommand := command.CreateCompleteTaskCommand(p.taskID(activeListIdx, activeTaskIdx), taskDoneIdx)
+	if err := p.command.Execute(completeTaskCommand); err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
 	p.redraw(activeListIdx)
 	p.redraw(taskDoneIdx)
 	if err := p.fixActiveTaskIdx(); err != nil {
@@ -367,12 +561,24 @@ func (p *BoardPage) focusLast() {
 	p.redraw(activeListIdx)
 }
 
+// shows why the boards cannot go to another state of the history, e.g. the
+// task a command changed was removed from the file on disk since
+func (p *BoardPage) historyFailed(err error) {
+	p.redrawBoard()
+	pages.AddPage("message", NewMessagePage(fmt.Sprintf("The change cannot be undone or redone:\n%v", err)), true, true)
+}
+
 func (p *BoardPage) undo() {
+	past := p.command.Past()
+	if len(past) == 0 {
+		return
+	}
 	if err := p.command.Undo(); err != nil {
-		app.Stop()
-		log.Fatal(err)
+		p.historyFailed(err)
+		return
 	}
-	p.redrawAll()
+	p.redrawBoard()
+	p.setStatus("Undid: " + past[0].Description)
 }
 
 func (p *BoardPage) editTask() {
@@ -388,14 +594,27 @@ func (p *BoardPage) editTask() {
 	pages.AddPage("edit", NewEditPage(p, activeListIdx, p.activeTaskIdxs[activeListIdx]), true, true)
 }
 
+func (p *BoardPage) openChecklist() {
+	activeListIdx := p.activeListIdx
+	taskCount, err := p.data.GetTaskCount(activeListIdx)
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	if taskCount < 1 {
+		return
+	}
+	pages.AddPage("checklist", NewChecklistPage(p, p.taskID(activeListIdx, p.activeTaskIdxs[activeListIdx])), true, true)
+}
+
 func (p *BoardPage) addTasksToList(listIdx int) {
-	tasks, err := p.data.GetTasks(listIdx)
+	tasks, err := p.data.GetListItems(listIdx)
 	if err != nil {
 		app.Stop()
 		log.Fatal(err)
 	}
 	for _, item := range tasks {
-		p.lists[listIdx].AddItem(item, "", 0, nil)
+		p.lists[listIdx].AddItem(formatTask(item), "", 0, nil)
 	}
 }
 
@@ -415,47 +634,94 @@ func (p *BoardPage) setInputCapture(i int) {
 			p.left()
 			return nil
 		case tcell.KeyCtrlR:
-			p.redo()
+			if !p.data.IsReadOnly() {
+				p.redo()
+			}
+		}
+		if event.Rune() == rune(tcell.KeyEnter) {
+			pages.AddPage("info", NewInfoPage(p, p.activeListIdx, p.activeTaskIdxs[p.activeListIdx]), true, true)
+			return event
 		}
-		switch event.Rune() {
-		case 'j', tcell.RuneDArrow:
+		action := p.actions[event.Rune()]
+		if p.data.IsReadOnly() && editActions[action] {
+			return nil
+		}
+		switch action {
+		case "down":
 			p.down()
-		case 'k', tcell.RuneUArrow:
+		case "up":
 			p.up()
-		case 'h', tcell.RuneLArrow:
+		case "left":
 			p.left()
-		case 'l', tcell.RuneRArrow:
+		case "right":
 			p.right()
-		case 'J':
+		case "move-down":
 			p.moveDown()
-		case 'K':
+		case "move-up":
 			p.moveUp()
-		case 'H':
+		case "move-left":
 			p.moveLeft()
-		case 'L':
+		case "move-right":
 			p.moveRight()
-		case 'a':
+		case "add":
 			p.addTask()
-		case 'A':
+		case "append":
 			p.appendTask()
-		case 'D':
+		case "delete":
 			p.removeTask()
-		case 'd':
+		case "done":
 			p.taskCompleted()
-		case 'e':
+		case "edit":
 			p.editTask()
-		case 'u':
+		case "checklist":
+			p.openChecklist()
+		case "next-board":
+			p.switchBoard(1)
+		case "prev-board":
+			p.switchBoard(-1)
+		case "move-board":
+			p.moveToBoard()
+		case "undo":
 			p.undo()
-		case 'q':
-			p.data.Save()
+		case "older":
+			p.stepState(-1)
+		case "newer":
+			p.stepState(1)
+		case "undo-tree":
+			pages.AddPage("undo-tree", NewUndoTreePage(p), true, true)
+		case "history":
+			pages.AddPage("history", NewHistoryPage(p), true, true)
+		case "backups":
+			p.openBackups()
+		case "archive":
+			p.archiveTasks()
+		case "show-archive":
+			p.openArchive()
+		case "add-list":
+			p.addList()
+		case "rename-list":
+			p.renameList()
+		case "delete-list":
+			p.deleteList()
+		case "list-left":
+			p.moveList(-1)
+		case "list-right":
+			p.moveList(1)
+		case "quit":
+			if !p.save() {
+				// quit again once the conflicts are resolved
+				return nil
+			}
+			err := p.autoCommit()
 			app.Stop()
-		case '?':
+			if err != nil {
+				log.Fatalf("Cannot commit the board file: %v", err)
+			}
+		case "help":
 			pages.AddPage("help", NewHelpPage(p), true, true)
-		case rune(tcell.KeyEnter):
-			pages.AddPage("info", NewInfoPage(p, p.activeListIdx, p.activeTaskIdxs[p.activeListIdx]), true, true)
-		case 'g':
+		case "first":
 			p.focusFirst()
-		case 'G':
+		case "last":
 			p.focusLast()
 		default:
 		}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	command "github.com/ppriyankuu/seiban/pkg/commands"
+	"github.com/rivo/tview"
+)
+
+// returns the names of the boards of the file as tabs, the shown board is highlighted
+func (p *BoardPage) boardTabs() string {
+	boardNames := p.data.GetBoardNames()
+	if len(boardNames) == 1 {
+		return "Board: " + tview.Escape(boardNames[0])
+	}
+	tabs := make([]string, len(boardNames))
+	for boardIdx, boardName := range boardNames {
+		if boardIdx == p.boardIdx {
+			tabs[boardIdx] = "[::r] " + tview.Escape(boardName) + " [::-]"
+			continue
+		}
+		tabs[boardIdx] = " " + tview.Escape(boardName) + " "
+	}
+	return "Boards: " + strings.Join(tabs, " ")
+}
+
+// shows the active board of the data, rebuilding its lists
+func (p *BoardPage) showBoard() {
+	p.boardIdx = p.data.GetActiveBoardIdx()
+	p.showLists(nil, 0)
+}
+
+// redraws the shown board, or shows the active board if an undo or redo switched to another one
+func (p *BoardPage) redrawBoard() {
+	if p.boardIdx != p.data.GetActiveBoardIdx() {
+		p.showBoard()
+		return
+	}
+	if len(p.lists) != p.data.GetListCount() {
+		// a list was added or removed
+		p.showLists(p.activeTaskIdxs, p.activeListIdx)
+		return
+	}
+	p.redrawAll()
+}
+
+// shows the next (offset 1) or previous (offset -1) board of the file, boards without lists are skipped
+func (p *BoardPage) switchBoard(offset int) {
+	boardCount := p.data.GetBoardCount()
+	for step := 1; step < boardCount; step++ {
+		boardIdx := ((p.boardIdx+offset*step)%boardCount + boardCount) % boardCount
+		listNames, err := p.data.GetBoardListNames(boardIdx)
+		if err != nil {
+			app.Stop()
+			log.Fatal(err)
+		}
+		if len(listNames) == 0 {
+			continue
+		}
+		if err := p.data.SetActiveBoard(boardIdx); err != nil {
+			app.Stop()
+			log.Fatal(err)
+		}
+		p.showBoard()
+		return
+	}
+}
+
+func (p *BoardPage) moveToBoard() {
+	activeListIdx := p.activeListIdx
+	taskCount, err := p.data.GetTaskCount(activeListIdx)
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	if taskCount < 1 || p.data.GetBoardCount() < 2 {
+		return
+	}
+	taskID := p.taskID(activeListIdx, p.activeTaskIdxs[activeListIdx])
+	pages.AddPage("move-board", NewMoveBoardPage(p, taskID), true, true)
+}
+
+// displays the lists of the other boards, the task is moved to the end of the selected one
+func NewMoveBoardPage(p *BoardPage, taskID string) tview.Primitive {
+	destinations := tview.NewList().ShowSecondaryText(false)
+	for boardIdx, boardName := range p.data.GetBoardNames() {
+		if boardIdx == p.boardIdx {
+			continue
+		}
+		listNames, err := p.data.GetBoardListNames(boardIdx)
+		if err != nil {
+			app.Stop()
+			log.Fatal(err)
+		}
+		for listIdx, listName := range listNames {
+			destinations.AddItem(tview.Escape(fmt.Sprintf("%s › %s", boardName, listName)), "", 0, func() {
+				moveTaskToBoardCommand := command.CreateMoveTaskToBoardCommand(taskID, boardIdx, listIdx)
+				if err := p.command.Execute(moveTaskToBoardCommand); err != nil {
+					app.Stop()
+					log.Fatal(err)
+				}
+				if err := p.fixActiveTaskIdx(); err != nil {
+					app.Stop()
+					log.Fatal(err)
+				}
+				p.redraw(p.activeListIdx)
+				closeMoveBoardPage()
+			})
+		}
+	}
+	destinations.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closeMoveBoardPage()
+			return nil
+		}
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case 'q':
+			closeMoveBoardPage()
+			return nil
+		}
+		return event
+	})
+	destinations.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitle("Move Task To").
+		SetTitleAlign(tview.AlignCenter)
+	width, height := GetSize()
+	return GetCenteredModal(destinations, width/2, height/2)
+}
+
+func closeMoveBoardPage() {
+	pages.RemovePage("move-board")
+	pages.SwitchToPage("board")
+}
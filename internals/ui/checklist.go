@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	command "github.com/ppriyankuu/seiban/pkg/commands"
+	"github.com/rivo/tview"
+)
+
+// all the information that the checklist page of a task requires
+type ChecklistPage struct {
+	board  *BoardPage
+	taskID string
+	list   *tview.List
+	input  *tview.InputField
+}
+
+// displays the checklist of a task, its items can be added, ticked, reordered and removed
+func NewChecklistPage(p *BoardPage, taskID string) tview.Primitive {
+	task, err := p.data.GetTaskByID(taskID)
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	c := &ChecklistPage{
+		board:  p,
+		taskID: taskID,
+		list:   tview.NewList().ShowSecondaryText(false),
+		input:  tview.NewInputField().SetLabel("New item: "),
+	}
+	c.input.SetFieldBackgroundColor(tcell.ColorWheat)
+	c.input.SetFieldTextColor(tcell.ColorBlack)
+	c.input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			c.addSubtask(strings.TrimSpace(c.input.GetText()))
+		}
+		c.input.SetText("")
+		app.SetFocus(c.list)
+	})
+	c.list.SetInputCapture(c.inputCapture)
+	c.redraw()
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(c.list, 0, 1, true).
+		AddItem(c.input, 1, 0, false)
+	layout.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitle("Checklist: " + tview.Escape(task.ItemName)).
+		SetTitleAlign(tview.AlignCenter)
+	width, height := GetSize()
+	return GetCenteredModal(layout, width/2, height/2)
+}
+
+func (c *ChecklistPage) redraw() {
+	subtasks, err := c.board.data.GetSubtasks(c.taskID)
+	if err != nil {
+		// the task is gone (e.g. after an undo)
+		closeChecklistPage()
+		return
+	}
+	curIdx := c.list.GetCurrentItem()
+	c.list.Clear()
+	for _, subtask := range subtasks {
+		checkbox := "[ ]"
+		if subtask.Done {
+			checkbox = "[x]"
+		}
+		c.list.AddItem(fmt.Sprintf("%s %s", tview.Escape(checkbox), tview.Escape(subtask.Name)), "", 0, nil)
+	}
+	if curIdx >= len(subtasks) {
+		curIdx = len(subtasks) - 1
+	}
+	c.list.SetCurrentItem(curIdx)
+	c.board.redrawAll()
+}
+
+func (c *ChecklistPage) execute(cmd command.Command) {
+	if err := c.board.command.Execute(cmd); err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	c.redraw()
+}
+
+func (c *ChecklistPage) addSubtask(name string) {
+	if len(name) <= 0 {
+		return
+	}
+	subtaskIdx := c.list.GetItemCount()
+	c.execute(command.CreateAddSubtaskCommand(c.taskID, name, subtaskIdx))
+	c.list.SetCurrentItem(subtaskIdx)
+}
+
+func (c *ChecklistPage) swapSubtask(offset int) {
+	curIdx := c.list.GetCurrentItem()
+	newIdx := curIdx + offset
+	if c.list.GetItemCount() == 0 || newIdx < 0 || newIdx >= c.list.GetItemCount() {
+		return
+	}
+	c.execute(command.CreateSwapSubtaskCommand(c.taskID, curIdx, newIdx))
+	c.list.SetCurrentItem(newIdx)
+}
+
+func (c *ChecklistPage) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEsc:
+		closeChecklistPage()
+		return nil
+	case tcell.KeyCtrlR:
+		c.board.redo()
+		c.redraw()
+		return nil
+	}
+	switch event.Rune() {
+	case 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case ' ', 'x':
+		if c.list.GetItemCount() > 0 {
+			c.execute(command.CreateToggleSubtaskCommand(c.taskID, c.list.GetCurrentItem()))
+		}
+	case 'a':
+		app.SetFocus(c.input)
+	case 'D':
+		if c.list.GetItemCount() > 0 {
+			c.execute(command.CreateRemoveSubtaskCommand(c.taskID, c.list.GetCurrentItem()))
+		}
+	case 'J':
+		c.swapSubtask(1)
+	case 'K':
+		c.swapSubtask(-1)
+	case 'u':
+		c.board.undo()
+		c.redraw()
+	case 'q':
+		closeChecklistPage()
+	default:
+		return event
+	}
+	return nil
+}
+
+func closeChecklistPage() {
+	pages.RemovePage("checklist")
+	pages.SwitchToPage("board")
+}
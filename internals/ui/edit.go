@@ -18,7 +18,9 @@ func NewEditPage(p *BoardPage, listIdx, taskIdx int) tview.Primitive {
 	width, height := GetSize()
 	form := tview.NewForm().
 		AddInputField("Task", task.ItemName, width/4, nil, nil).
-		AddInputField("Task Description", task.ItemDescription, width/4, nil, nil)
+		AddTextArea("Task Description", task.ItemDescription, width/4, 5, 0, nil)
+	form = addMetadataFields(form, *task, width/4).
+		AddCheckbox("Done", task.Done, nil)
 	form.SetFieldBackgroundColor(tcell.ColorWheat)  // Set background color of input fields
 	form.SetFieldTextColor(tcell.ColorBlack)        // Set text color for better contrast
 	form.SetButtonBackgroundColor(tcell.ColorWheat) // Set button background color
@@ -31,10 +33,17 @@ func NewEditPage(p *BoardPage, listIdx, taskIdx int) tview.Primitive {
 		if len(taskName) <= 0 {
 			return
 		}
-		taskDesc := form.GetFormItemByLabel("Task Description").(*tview.InputField).GetText()
-		taskDesc = strings.TrimSpace(taskDesc)
+		taskDesc := form.GetFormItemByLabel("Task Description").(*tview.TextArea).GetText()
+		taskDesc = trimDescription(taskDesc)
+		editedTask := *task
+		editedTask.ItemName = taskName
+		editedTask.ItemDescription = taskDesc
+		if !readMetadataFields(form, &editedTask) {
+			return
+		}
+		editedTask.Done = form.GetFormItemByLabel("Done").(*tview.Checkbox).IsChecked()
 		activeListIdx := p.activeListIdx
-		editTaskCommand := command.CreateEditTaskCommand(activeListIdx, p.activeTaskIdxs[activeListIdx], taskName, taskDesc)
+		editTaskCommand := command.CreateEditTaskCommand(task.ID, editedTask)
 		if err := p.command.Execute(editTaskCommand); err != nil {
 			app.Stop()
 			log.Fatal(err)
@@ -10,34 +10,57 @@ const helpText = `
   
 	Navigation
 	────────────────────────────────
-    j → Move down
-    k → Move up
-    h → Move left
-    l → Move right
-    g → Focus first
-    G → Focus last
+    {down} → Move down
+    {up} → Move up
+    {left} → Move left
+    {right} → Move right
+    {first} → Focus first
+    {last} → Focus last
+    {next-board} / {prev-board} → Next / previous board
 	
 	Task Management
 	────────────────────────────────
-    a → Add under cursor
-    A → Add at end
-    d → Mark as done
-    D → Delete
-    e → Edit task
+    {add} → Add under cursor
+    {append} → Add at end
+    {done} → Mark as done
+    {delete} → Delete
+    {edit} → Edit task
+    {checklist} → Checklist
+    {archive} → Archive done tasks
+    {show-archive} → Browse the archive
+	
+	Checklist
+	────────────────────────────────
+    a → Add item
+    x → Tick / untick item
+    J / K → Move item down / up
+    D → Delete item
 	
 	Movement
 	────────────────────────────────
-    L → Move right
-    H → Move left
-    J → Move down
-    K → Move up
+    {move-right} → Move right
+    {move-left} → Move left
+    {move-down} → Move down
+    {move-up} → Move up
+    {move-board} → Move to another board
+	
+	Lists
+	────────────────────────────────
+    {add-list} → Add list
+    {rename-list} → Rename list
+    {delete-list} → Delete list
+    {list-left} / {list-right} → Move list left / right
 	
 	Actions
 	────────────────────────────────
     Enter → View info  
-    u → Undo
+    {undo} → Undo
     Ctrl+R → Redo
-    q → Quit
+    {older} / {newer} → Older / newer state
+    {undo-tree} → Undo tree
+    {history} → History
+    {backups} → Backups
+    {quit} → Quit
 
 	────────────────────────────────
 `
@@ -45,7 +68,7 @@ const helpText = `
 // displays the help page that contains all the keybinds of the application
 func NewHelpPage(p *BoardPage) tview.Primitive {
 	help := tview.NewModal().
-		SetText(helpText).
+		SetText(withKeys(helpText, p.keys)).
 		SetBackgroundColor(theme.PrimitiveBackgroundColor).
 		AddButtons([]string{"OK"}).
 		SetDoneFunc(func(_ int, buttonLabel string) {
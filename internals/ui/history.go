@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	command "github.com/ppriyankuu/seiban/pkg/commands"
+	"github.com/rivo/tview"
+)
+
+// all the information that the history page requires
+type HistoryPage struct {
+	board *BoardPage
+	list  *tview.List
+	// the state every item of the list goes to, -1 for the current state
+	states []int
+}
+
+// displays the commands redo would redo, the last one first, then the
+// commands undo would undo, the last one executed first. the commands are
+// undone or redone up to the selected one.
+func NewHistoryPage(p *BoardPage) tview.Primitive {
+	h := &HistoryPage{
+		board: p,
+		list:  tview.NewList().ShowSecondaryText(false),
+	}
+	h.list.SetSelectedFunc(func(idx int, _, _ string, _ rune) {
+		state := h.states[idx]
+		if state < 0 {
+			return
+		}
+		if err := p.command.GoTo(state); err != nil {
+			p.historyFailed(err)
+		}
+		p.redrawBoard()
+		p.showState()
+		h.redraw()
+	})
+	h.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closeHistoryPage()
+			return nil
+		}
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case 'q':
+			closeHistoryPage()
+			return nil
+		}
+		return event
+	})
+	h.redraw()
+	h.list.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitle("History (Enter: undo or redo up to the command)").
+		SetTitleAlign(tview.AlignCenter)
+	width, height := GetSize()
+	return GetCenteredModal(h.list, width*3/4, height*3/4)
+}
+
+// shows the commands that can be redone above the current state, and the
+// ones that can be undone below it, the current state selected
+func (h *HistoryPage) redraw() {
+	future := h.board.command.Future()
+	past := h.board.command.Past()
+	h.list.Clear()
+	h.states = nil
+	// redoing a command goes to the state it made
+	for idx := len(future) - 1; idx >= 0; idx-- {
+		h.addState("[::d]redo  "+historyText(future[idx])+"[::-]", future[idx].ID)
+	}
+	h.addState("──── now ────", -1)
+	current := h.list.GetItemCount() - 1
+	// undoing a command goes to the state it was executed in
+	for _, state := range past {
+		h.addState("undo  "+historyText(state), state.Parent)
+	}
+	h.list.SetCurrentItem(current)
+}
+
+func (h *HistoryPage) addState(text string, state int) {
+	h.list.AddItem(text, "", 0, nil)
+	h.states = append(h.states, state)
+}
+
+func historyText(state command.HistoryState) string {
+	return fmt.Sprintf("%v  %v", state.Time.Format(time.DateTime), tview.Escape(state.Description))
+}
+
+func closeHistoryPage() {
+	pages.RemovePage("history")
+	pages.SwitchToPage("board")
+}
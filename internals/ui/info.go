@@ -15,8 +15,12 @@ func NewInfoPage(p *BoardPage, listIdx, taskIdx int) tview.Primitive {
 		app.Stop()
 		log.Fatal(err)
 	}
+	infoText := fmt.Sprintf("Task: %v (id: %v)\n Task Description: %v", task.ItemName, task.ID, task.ItemDescription)
+	if metadata := formatTaskMetadata(*task); len(metadata) > 0 {
+		infoText += "\n" + metadata
+	}
 	info := tview.NewModal().
-		SetText(fmt.Sprintf("Task: %v\n Task Description: %v", task.ItemName, task.ItemDescription)).
+		SetText(infoText).
 		AddButtons([]string{"OK"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			if buttonLabel == "OK" {
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// keys of the actions of the board, they can be changed in the "keys"
+// section of the front matter (e.g. "done: x")
+var defaultKeys = map[string]rune{
+	"down":         'j',
+	"up":           'k',
+	"left":         'h',
+	"right":        'l',
+	"move-down":    'J',
+	"move-up":      'K',
+	"move-left":    'H',
+	"move-right":   'L',
+	"add":          'a',
+	"append":       'A',
+	"delete":       'D',
+	"done":         'd',
+	"edit":         'e',
+	"checklist":    'c',
+	"undo":         'u',
+	"older":        '-',
+	"newer":        '+',
+	"undo-tree":    'U',
+	"history":      'v',
+	"quit":         'q',
+	"help":         '?',
+	"first":        'g',
+	"last":         'G',
+	"next-board":   'b',
+	"prev-board":   'B',
+	"move-board":   'M',
+	"backups":      'R',
+	"archive":      'x',
+	"show-archive": 'X',
+	"add-list":     'n',
+	"rename-list":  'r',
+	"delete-list":  'z',
+	"list-left":    '<',
+	"list-right":   '>',
+}
+
+// actions that change the board, ignored when the board is opened read-only
+var editActions = map[string]bool{
+	"move-down":   true,
+	"move-up":     true,
+	"move-left":   true,
+	"move-right":  true,
+	"add":         true,
+	"append":      true,
+	"delete":      true,
+	"done":        true,
+	"edit":        true,
+	"checklist":   true,
+	"move-board":  true,
+	"undo":        true,
+	"older":       true,
+	"newer":       true,
+	"undo-tree":   true,
+	"history":     true,
+	"archive":     true,
+	"add-list":    true,
+	"rename-list": true,
+	"delete-list": true,
+	"list-left":   true,
+	"list-right":  true,
+}
+
+// returns the key of every action, with the overrides of the front matter applied.
+// overrides that are not a single character or name an unknown action are ignored.
+// an action whose key is given to another action has no key, unless it is given
+// a new one too (e.g. "done: x" and "archive: d" swap the keys).
+func actionKeys(overrides map[string]string) map[string]rune {
+	keys := make(map[string]rune, len(defaultKeys))
+	for action, key := range defaultKeys {
+		keys[action] = key
+	}
+	overridden := make(map[rune]bool)
+	for action, key := range overrides {
+		if _, ok := keys[action]; !ok || utf8.RuneCountInString(key) != 1 {
+			continue
+		}
+		keys[action], _ = utf8.DecodeRuneInString(key)
+		overridden[keys[action]] = true
+	}
+	for action, key := range keys {
+		if _, ok := overrides[action]; !ok && overridden[key] {
+			delete(keys, action)
+		}
+	}
+	return keys
+}
+
+// returns the key of an action as it is shown, "none" for an action that has no key
+func keyName(keys map[string]rune, action string) string {
+	if key, ok := keys[action]; ok {
+		return string(key)
+	}
+	return "none"
+}
+
+// returns the action bound to every key
+func keymap(keys map[string]rune) map[rune]string {
+	actions := make(map[rune]string, len(keys)+4)
+	actions[tcell.RuneDArrow] = "down"
+	actions[tcell.RuneUArrow] = "up"
+	actions[tcell.RuneLArrow] = "left"
+	actions[tcell.RuneRArrow] = "right"
+	for action, key := range keys {
+		actions[key] = action
+	}
+	return actions
+}
+
+// replaces the {action} placeholders of the help text with the keys of the actions
+func withKeys(text string, keys map[string]rune) string {
+	var replacements []string
+	for action := range defaultKeys {
+		replacements = append(replacements, "{"+action+"}", keyName(keys, action))
+	}
+	return strings.NewReplacer(replacements...).Replace(text)
+}
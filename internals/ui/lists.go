@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	command "github.com/ppriyankuu/seiban/pkg/commands"
+	"github.com/rivo/tview"
+)
+
+// asks for the title of a new list, added to the right of the active list
+func (p *BoardPage) addList() {
+	pages.AddPage("list-title", NewListTitlePage("Add List", "", func(listTitle string) {
+		if !p.checkListTitle(listTitle, -1) {
+			return
+		}
+		listIdx := p.activeListIdx + 1
+		p.executeListCommand(command.CreateAddListCommand(listIdx, listTitle))
+		activeTaskIdxs := append([]int{}, p.activeTaskIdxs[:listIdx]...)
+		activeTaskIdxs = append(activeTaskIdxs, 0)
+		p.showLists(append(activeTaskIdxs, p.activeTaskIdxs[listIdx:]...), listIdx)
+	}), true, true)
+}
+
+// asks for the new title of the active list
+func (p *BoardPage) renameList() {
+	listIdx := p.activeListIdx
+	pages.AddPage("list-title", NewListTitlePage("Rename List", p.data.GetListNames()[listIdx], func(listTitle string) {
+		if !p.checkListTitle(listTitle, listIdx) {
+			return
+		}
+		p.executeListCommand(command.CreateRenameListCommand(listIdx, listTitle))
+		p.redraw(listIdx)
+		app.SetFocus(p.lists[listIdx])
+	}), true, true)
+}
+
+// asks whether to delete the active list, and what to do with its tasks
+func (p *BoardPage) deleteList() {
+	listIdx := p.activeListIdx
+	listNames := p.data.GetListNames()
+	if len(listNames) == 1 {
+		pages.AddPage("message", NewMessagePage("The last list of a board cannot be deleted."), true, true)
+		return
+	}
+	taskCount, err := p.data.GetTaskCount(listIdx)
+	if err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+	// the tasks go to the list on the left, or on the right for the first list
+	destListIdx := listIdx - 1
+	if destListIdx < 0 {
+		destListIdx = 1
+	}
+	text := fmt.Sprintf("Delete the list %q?", listNames[listIdx])
+	buttons := []string{"Delete", "Cancel"}
+	moveButton := fmt.Sprintf("Move them to %v", listNames[destListIdx])
+	if taskCount > 0 {
+		text = fmt.Sprintf("Delete the list %q?\nIt has %v tasks.", listNames[listIdx], taskCount)
+		buttons = []string{moveButton, "Delete them too", "Cancel"}
+	}
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetDoneFunc(func(_ int, buttonLabel string) {
+			pages.RemovePage("delete-list")
+			switch buttonLabel {
+			case "Delete", "Delete them too":
+				destListIdx = -1
+			case moveButton:
+			default:
+				return
+			}
+			p.executeListCommand(command.CreateRemoveListCommand(listIdx, destListIdx))
+			activeTaskIdxs := append([]int{}, p.activeTaskIdxs[:listIdx]...)
+			p.showLists(append(activeTaskIdxs, p.activeTaskIdxs[listIdx+1:]...), max(0, listIdx-1))
+		})
+	styleModal(modal)
+	pages.AddPage("delete-list", modal, true, true)
+}
+
+// moves the active list to the left (offset -1) or right (offset 1) of its neighbour
+func (p *BoardPage) moveList(offset int) {
+	listIdx := p.activeListIdx
+	newListIdx := listIdx + offset
+	if newListIdx < 0 || newListIdx >= len(p.lists) {
+		return
+	}
+	p.executeListCommand(command.CreateMoveListCommand(listIdx, newListIdx))
+	activeTaskIdxs := append([]int{}, p.activeTaskIdxs...)
+	activeTaskIdxs[listIdx], activeTaskIdxs[newListIdx] = activeTaskIdxs[newListIdx], activeTaskIdxs[listIdx]
+	p.showLists(activeTaskIdxs, newListIdx)
+}
+
+// reports whether a list, listIdx or -1 for a new one, can have the title.
+// the reason it cannot (e.g. another list has it) is shown.
+func (p *BoardPage) checkListTitle(listTitle string, listIdx int) bool {
+	if err := p.data.CheckListTitle(listTitle, listIdx); err != nil {
+		pages.AddPage("message", NewMessagePage(err.Error()), true, true)
+		return false
+	}
+	return true
+}
+
+func (p *BoardPage) executeListCommand(listCommand command.Command) {
+	if err := p.command.Execute(listCommand); err != nil {
+		app.Stop()
+		log.Fatal(err)
+	}
+}
+
+// shows the lists of the board again after lists were added, removed or
+// moved, with the cursors of the lists at activeTaskIdxs and the list at
+// activeListIdx focused
+func (p *BoardPage) showLists(activeTaskIdxs []int, activeListIdx int) {
+	listCount := p.data.GetListCount()
+	p.lists = make([]*tview.List, listCount)
+	p.activeTaskIdxs = make([]int, listCount)
+	for listIdx := range listCount {
+		taskCount, err := p.data.GetTaskCount(listIdx)
+		if err != nil {
+			app.Stop()
+			log.Fatal(err)
+		}
+		if listIdx < len(activeTaskIdxs) {
+			p.activeTaskIdxs[listIdx] = max(0, min(activeTaskIdxs[listIdx], taskCount-1))
+		}
+	}
+	p.activeListIdx = max(0, min(activeListIdx, listCount-1))
+	pages.AddPage("board", p.Page(), true, true)
+	p.lists[0].SetBorderColor(theme.PrimitiveBackgroundColor)
+	p.lists[p.activeListIdx].SetBorderColor(theme.ContrastBackgroundColor)
+	p.redrawAll()
+	app.SetFocus(p.lists[p.activeListIdx])
+}
+
+// asks for the title of a list, save is called with it once the page is closed
+func NewListTitlePage(formTitle, listTitle string, save func(listTitle string)) tview.Primitive {
+	width, height := GetSize()
+	form := tview.NewForm().
+		AddInputField("List", listTitle, width/4, nil, nil)
+	form.SetFieldBackgroundColor(tcell.ColorWheat)
+	form.SetFieldTextColor(tcell.ColorBlack)
+	form.SetButtonBackgroundColor(tcell.ColorWheat)
+	form.SetButtonTextColor(tcell.ColorBlack)
+	form.SetBorderColor(theme.BorderColor)
+	form.AddButton("Save", func() {
+		listTitle := strings.TrimSpace(form.GetFormItemByLabel("List").(*tview.InputField).GetText())
+		if len(listTitle) == 0 {
+			return
+		}
+		pages.RemovePage("list-title")
+		save(listTitle)
+	}).AddButton("Cancel", func() {
+		closeListTitlePage()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closeListTitlePage()
+		}
+		return event
+	})
+	form.SetBorder(true).SetTitle(formTitle).SetTitleAlign(tview.AlignCenter)
+	return GetCenteredModal(form, width/2, height/4)
+}
+
+func closeListTitlePage() {
+	pages.RemovePage("list-title")
+	pages.SwitchToPage("board")
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/ppriyankuu/seiban/pkg/parser"
+	"github.com/rivo/tview"
+)
+
+// pages that show a task, closed when the board is reloaded as the task may be gone
+var taskPages = []string{"info", "edit", "checklist", "move-board"}
+
+// called when the board file changes on disk. the board is reloaded, or if
+// it has changes that are not saved yet, merged with the file and saved.
+// tasks changed on both sides are shown one by one for the user to pick a
+// version.
+func (p *BoardPage) fileChanged() {
+	if pages.HasPage("conflict") {
+		// checked again once the conflicts are resolved
+		return
+	}
+	changed, err := p.data.FileChanged()
+	if err != nil || !changed {
+		// the file is gone for a moment while it is replaced, or the change is our own save
+		return
+	}
+	if !p.data.HasUnsavedChanges() {
+		p.updateFromFile(func() (bool, error) {
+			return true, p.data.Reload()
+		})
+		return
+	}
+	p.save()
+}
+
+// shows the first of the conflicts, or saves the merged board once there are none left
+func (p *BoardPage) resolveConflicts(conflicts []parser.MergeConflict) {
+	if len(conflicts) > 0 {
+		pages.AddPage("conflict", NewConflictPage(p, conflicts), true, true)
+		return
+	}
+	// merges the file first if it changed again while the conflicts were resolved
+	p.save()
+}
+
+// runs update, which changes the data without a command (e.g. replaces it by
+// the file on disk or merges the file into it), then shows
+// the board again with the cursor on the same task if it is still there.
+// update reports whether the undo history is kept: its commands refer to
+// tasks by id, they still apply once the changes on disk are merged in, but
+// not once a task changed on both sides was replaced by the version on disk.
+// reports false if the update failed.
+func (p *BoardPage) updateFromFile(update func() (bool, error)) bool {
+	activeListIdx := p.activeListIdx
+	activeTaskIdxs := p.activeTaskIdxs
+	activeTaskID := ""
+	if taskCount, err := p.data.GetTaskCount(activeListIdx); err == nil && taskCount > 0 {
+		activeTaskID = p.taskID(activeListIdx, activeTaskIdxs[activeListIdx])
+	}
+	keepHistory, err := update()
+	if err != nil {
+		pages.AddPage("message", NewMessagePage(fmt.Sprintf("The board cannot be updated:\n%v", err)), true, true)
+		return false
+	}
+	if !keepHistory {
+		p.command = p.newCommandManager()
+		// the status is about the history that was dropped
+		p.status = ""
+	}
+	for _, name := range taskPages {
+		pages.RemovePage(name)
+	}
+	p.showBoard()
+	for listIdx := range p.lists {
+		if listIdx < len(activeTaskIdxs) {
+			p.activeTaskIdxs[listIdx] = activeTaskIdxs[listIdx]
+		}
+		taskCount, _ := p.data.GetTaskCount(listIdx)
+		p.activeTaskIdxs[listIdx] = max(0, min(p.activeTaskIdxs[listIdx], taskCount-1))
+	}
+	if listIdx, taskIdx, err := p.data.FindTask(activeTaskID); len(activeTaskID) > 0 && err == nil {
+		p.activeListIdx = listIdx
+		p.activeTaskIdxs[listIdx] = taskIdx
+	} else if activeListIdx < len(p.lists) {
+		p.activeListIdx = activeListIdx
+	}
+	p.redrawAll()
+	app.SetFocus(p.lists[p.activeListIdx])
+	return true
+}
+
+// shows a task changed both on the board and in the file on disk,
+// and lets the user pick the version to keep
+func NewConflictPage(p *BoardPage, conflicts []parser.MergeConflict) tview.Primitive {
+	conflict := conflicts[0]
+	if len(conflict.Structure) > 0 {
+		return newStructureConflictPage(p, conflicts)
+	}
+	text := fmt.Sprintf("This task was changed both here and in the file on disk (conflicts left: %v).\n\nHere (%v):\n%v\n\nOn disk (%v):\n%v",
+		len(conflicts), conflict.OursList, formatConflictTask(conflict.Ours), conflict.TheirsList, formatConflictTask(conflict.Theirs))
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"Keep mine", "Take the one on disk"}).
+		SetDoneFunc(func(_ int, buttonLabel string) {
+			pages.RemovePage("conflict")
+			keepOurs := buttonLabel == "Keep mine"
+			p.updateFromFile(func() (bool, error) {
+				return keepOurs, p.data.ResolveConflict(conflict, keepOurs)
+			})
+			p.resolveConflicts(conflicts[1:])
+		})
+	styleModal(modal)
+	return modal
+}
+
+// shows a change to the boards or lists made both on the board and in the
+// file on disk that cannot be merged, the board keeps the change made here
+func newStructureConflictPage(p *BoardPage, conflicts []parser.MergeConflict) tview.Primitive {
+	conflict := conflicts[0]
+	text := fmt.Sprintf("This was changed both here and in the file on disk (conflicts left: %v):\n%v\n\nHere: %v\nOn disk: %v\n\nThe board keeps the change made here. A board or list removed on one side but changed on the other is kept.",
+		len(conflicts), tview.Escape(conflict.Structure), tview.Escape(conflict.OursChange), tview.Escape(conflict.TheirsChange))
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(_ int, _ string) {
+			pages.RemovePage("conflict")
+			p.resolveConflicts(conflicts[1:])
+		})
+	styleModal(modal)
+	return modal
+}
+
+func formatConflictTask(task *parser.ListItem) string {
+	if task == nil {
+		return "(removed)"
+	}
+	text := formatTask(*task)
+	if len(task.ItemDescription) > 0 {
+		text += "\n" + tview.Escape(task.ItemDescription)
+	}
+	return text
+}
+
+// displays a message with an OK button
+func NewMessagePage(text string) tview.Primitive {
+	message := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(_ int, _ string) {
+			pages.RemovePage("message")
+			pages.SwitchToPage("board")
+		})
+	styleModal(message)
+	return message
+}
+
+func styleModal(modal *tview.Modal) {
+	modal.SetBorderColor(theme.BorderColor)
+	modal.SetTextColor(tcell.ColorBlack)
+	modal.SetButtonBackgroundColor(tcell.ColorWheat)
+	modal.SetButtonTextColor(tcell.ColorBlack)
+	modal.SetBackgroundColor(theme.PrimitiveBackgroundColor)
+}
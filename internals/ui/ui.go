@@ -29,19 +29,68 @@ func defaultTheme() *tview.Theme {
 	}
 }
 
-func Start(fileName string) error {
+// returns the default theme with the colors of the front matter applied,
+// colors are looked up by name (e.g. "wheat", "red") or given as a quoted "#rrggbb".
+func themeFromConfig(colors map[string]string) *tview.Theme {
+	t := defaultTheme()
+	elements := map[string]*tcell.Color{
+		"background":              &t.PrimitiveBackgroundColor,
+		"contrast":                &t.ContrastBackgroundColor,
+		"more-contrast":           &t.MoreContrastBackgroundColor,
+		"border":                  &t.BorderColor,
+		"title":                   &t.TitleColor,
+		"graphics":                &t.GraphicsColor,
+		"text":                    &t.PrimaryTextColor,
+		"secondary-text":          &t.SecondaryTextColor,
+		"tertiary-text":           &t.TertiaryTextColor,
+		"inverse-text":            &t.InverseTextColor,
+		"contrast-secondary-text": &t.ContrastSecondaryTextColor,
+	}
+	for element, colorName := range colors {
+		color, ok := elements[element]
+		if !ok {
+			continue
+		}
+		if c := tcell.GetColor(colorName); c != tcell.ColorDefault {
+			*color = c
+		}
+	}
+	return t
+}
+
+// runs the app on the board file, the lines of the file that look like a
+// mistake are shown as warnings when it starts
+func Start(fileName string, readOnly bool) error {
 	app = tview.NewApplication()
-	initiate(fileName)
+	boardPage, err := initiate(fileName, readOnly)
+	if err != nil {
+		return err
+	}
+	// reloading the board when the file is changed by an editor, git, ...
+	stopWatching, err := boardPage.data.Watch(func() {
+		app.QueueUpdateDraw(boardPage.fileChanged)
+	})
+	if err != nil {
+		return err
+	}
+	defer stopWatching()
 	if err := app.Run(); err != nil {
 		return fmt.Errorf("Error running the app: %s", err)
 	}
 	return nil
 }
 
-func initiate(fileName string) {
-	theme = defaultTheme()
-	boardPage := NewBoardPage(fileName)
+func initiate(fileName string, readOnly bool) (*BoardPage, error) {
+	boardPage, err := NewBoardPage(fileName, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	theme = boardPage.theme
 	boardPageFrame := boardPage.Page()
 	pages = tview.NewPages().AddPage("board", boardPageFrame, true, true)
 	app.SetRoot(pages, true).SetFocus(boardPageFrame)
+	if warnings := boardPage.data.Warnings(); len(warnings) > 0 {
+		pages.AddPage("message", NewMessagePage("These lines of the board file look wrong, they are kept as they are:\n\n"+tview.Escape(warnings.Error())), true, true)
+	}
+	return boardPage, nil
 }
@@ -1,6 +1,11 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/parser"
 	"github.com/rivo/tview"
 	"golang.org/x/term"
 )
@@ -22,3 +27,92 @@ func GetSize() (width, height int) {
 	}
 	return width, height
 }
+
+// removes the blank lines and trailing whitespace around a description,
+// the indentation of its first line is kept.
+func trimDescription(description string) string {
+	description = strings.TrimRight(description, " \t\n")
+	for {
+		firstLine, rest, found := strings.Cut(description, "\n")
+		if !found || len(strings.TrimSpace(firstLine)) > 0 {
+			return description
+		}
+		description = rest
+	}
+}
+
+// returns the text shown for a task on the board, the name followed by its metadata
+func formatTask(task parser.ListItem) string {
+	text := tview.Escape(task.ItemName)
+	if task.Done {
+		text = "[green]✓[-] " + text
+	}
+	if done, total := task.SubtaskProgress(); total > 0 {
+		text += fmt.Sprintf(" (%d/%d)", done, total)
+	}
+	switch task.Priority {
+	case parser.PriorityHigh:
+		text += " [red]!high[-]"
+	case parser.PriorityMedium:
+		text += " [orange]!medium[-]"
+	case parser.PriorityLow:
+		text += " [green]!low[-]"
+	}
+	if !task.DueDate.IsZero() {
+		text += fmt.Sprintf(" [::u]due %s[::-]", task.DueDate.Format(parser.DueDateLayout))
+	}
+	for _, tag := range task.Tags {
+		text += " [::i]#" + tview.Escape(tag) + "[::-]"
+	}
+	return text
+}
+
+// returns the metadata of a task as shown on the info page
+func formatTaskMetadata(task parser.ListItem) string {
+	var metadata []string
+	if task.Priority != parser.PriorityNone {
+		metadata = append(metadata, "Priority: "+task.Priority.String())
+	}
+	if !task.DueDate.IsZero() {
+		metadata = append(metadata, "Due: "+task.DueDate.Format(parser.DueDateLayout))
+	}
+	if !task.DoneDate.IsZero() {
+		metadata = append(metadata, "Done: "+task.DoneDate.Format(parser.DueDateLayout))
+	}
+	if len(task.Tags) > 0 {
+		metadata = append(metadata, "Tags: #"+strings.Join(task.Tags, " #"))
+	}
+	return strings.Join(metadata, "\n")
+}
+
+// adds the fields for the metadata of a task to a form
+func addMetadataFields(form *tview.Form, task parser.ListItem, fieldWidth int) *tview.Form {
+	dueDate := ""
+	if !task.DueDate.IsZero() {
+		dueDate = task.DueDate.Format(parser.DueDateLayout)
+	}
+	return form.
+		AddDropDown("Priority", parser.PriorityNames(), int(task.Priority), nil).
+		AddInputField("Due Date", dueDate, fieldWidth, nil, nil).
+		AddInputField("Tags", strings.Join(task.Tags, " "), fieldWidth, nil, nil)
+}
+
+// reads the metadata fields of a form into the task,
+// returns false if one of the fields is not valid.
+func readMetadataFields(form *tview.Form, task *parser.ListItem) bool {
+	priorityIdx, _ := form.GetFormItemByLabel("Priority").(*tview.DropDown).GetCurrentOption()
+	dueDateText := form.GetFormItemByLabel("Due Date").(*tview.InputField).GetText()
+	dueDateText = strings.TrimSpace(dueDateText)
+	var dueDate time.Time
+	if len(dueDateText) > 0 {
+		var err error
+		dueDate, err = time.Parse(parser.DueDateLayout, dueDateText)
+		if err != nil {
+			return false
+		}
+	}
+	task.Priority = parser.Priority(priorityIdx)
+	task.DueDate = dueDate
+	task.Tags = parser.ParseTags(form.GetFormItemByLabel("Tags").(*tview.InputField).GetText())
+	return true
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const undoTreeInputLabel = "Go back in time (e.g. 10m, +10m forward): "
+
+// all the information that the undo tree page requires
+type UndoTreePage struct {
+	board *BoardPage
+	list  *tview.List
+	input *tview.InputField
+	// the state of every item of the list
+	states []int
+}
+
+// goes to the state made before (offset -1) or after (offset 1) the current one, like vim's g- and g+
+func (p *BoardPage) stepState(offset int) {
+	goTo := p.command.Older
+	if offset > 0 {
+		goTo = p.command.Newer
+	}
+	if err := goTo(); err != nil {
+		p.historyFailed(err)
+		return
+	}
+	p.redrawBoard()
+	p.showState()
+}
+
+// shows the state the boards went to in the status
+func (p *BoardPage) showState() {
+	state := p.command.States()[p.command.State()]
+	if state.ID == 0 {
+		p.setStatus("Went to the first state")
+		return
+	}
+	p.setStatus(fmt.Sprintf("Went to state %v: %v", state.ID, state.Description))
+}
+
+// displays the states of the history as a tree, the selected state is
+// gone to. a time like 10m (earlier) or +10m (later) goes back or forward
+// in time, like vim's :earlier and :later.
+func NewUndoTreePage(p *BoardPage) tview.Primitive {
+	u := &UndoTreePage{
+		board: p,
+		list:  tview.NewList().ShowSecondaryText(false),
+		input: tview.NewInputField().SetLabel(undoTreeInputLabel),
+	}
+	u.input.SetFieldBackgroundColor(tcell.ColorWheat)
+	u.input.SetFieldTextColor(tcell.ColorBlack)
+	u.input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			u.travel(strings.TrimSpace(u.input.GetText()))
+		}
+		u.input.SetText("")
+		app.SetFocus(u.list)
+	})
+	u.list.SetSelectedFunc(func(idx int, _, _ string, _ rune) {
+		state := u.states[idx]
+		u.goTo(func() error { return u.board.command.GoTo(state) })
+	})
+	u.list.SetInputCapture(u.inputCapture)
+	u.redraw()
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(u.list, 0, 1, true).
+		AddItem(u.input, 1, 0, false)
+	layout.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitle("Undo tree (Enter: go to state, t: go back in time)").
+		SetTitleAlign(tview.AlignCenter)
+	width, height := GetSize()
+	return GetCenteredModal(layout, width*3/4, height*3/4)
+}
+
+// shows the states, the current one selected. the states the boards went
+// through to the current one are bright, the undone branches dim.
+func (u *UndoTreePage) redraw() {
+	states := u.board.command.States()
+	current := u.board.command.State()
+	onPath := make([]bool, len(states))
+	for state := current; state >= 0; state = states[state].Parent {
+		onPath[state] = true
+	}
+	children := make([][]int, len(states))
+	for _, state := range states[1:] {
+		children[state.Parent] = append(children[state.Parent], state.ID)
+	}
+	u.list.Clear()
+	u.states = nil
+	// the newest child of a state goes on below it, the older ones branch off
+	var addState func(state, depth int)
+	addState = func(state, depth int) {
+		marker := "○"
+		if state == current {
+			marker = "●"
+		}
+		description := states[state].Description
+		if state == 0 {
+			description = "(the first state)"
+		}
+		text := fmt.Sprintf("%v%v %3d  %v  %v", strings.Repeat("│ ", depth), marker, state, states[state].Time.Format(time.DateTime), tview.Escape(description))
+		if !onPath[state] {
+			text = "[::d]" + text + "[::-]"
+		}
+		u.list.AddItem(text, "", 0, nil)
+		u.states = append(u.states, state)
+		stateChildren := children[state]
+		for idx, child := range stateChildren {
+			if idx < len(stateChildren)-1 {
+				addState(child, depth+1)
+			} else {
+				addState(child, depth)
+			}
+		}
+	}
+	addState(0, 0)
+	for idx, state := range u.states {
+		if state == current {
+			u.list.SetCurrentItem(idx)
+		}
+	}
+}
+
+// runs goTo, which goes to another state, then shows the board and the tree as they are in it
+func (u *UndoTreePage) goTo(goTo func() error) {
+	if err := goTo(); err != nil {
+		u.board.historyFailed(err)
+	}
+	u.board.redrawBoard()
+	u.board.showState()
+	u.redraw()
+}
+
+// goes back in time by a duration like 10m, or forward by one like +10m
+func (u *UndoTreePage) travel(text string) {
+	if len(text) == 0 {
+		return
+	}
+	travel := u.board.command.Earlier
+	if strings.HasPrefix(text, "+") {
+		travel = u.board.command.Later
+	}
+	duration, err := time.ParseDuration(strings.TrimLeft(text, "+-"))
+	if err != nil {
+		u.input.SetLabel(fmt.Sprintf("%q is not a time like 10m or +1h30m, try again: ", text))
+		return
+	}
+	u.input.SetLabel(undoTreeInputLabel)
+	u.goTo(func() error { return travel(duration) })
+}
+
+func (u *UndoTreePage) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEsc:
+		closeUndoTreePage()
+		return nil
+	}
+	if event.Key() == tcell.KeyRune {
+		switch u.board.actions[event.Rune()] {
+		case "older":
+			u.goTo(u.board.command.Older)
+			return nil
+		case "newer":
+			u.goTo(u.board.command.Newer)
+			return nil
+		}
+	}
+	switch event.Rune() {
+	case 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case 't':
+		app.SetFocus(u.input)
+		return nil
+	case 'q':
+		closeUndoTreePage()
+		return nil
+	}
+	return event
+}
+
+func closeUndoTreePage() {
+	pages.RemovePage("undo-tree")
+	pages.SwitchToPage("board")
+}
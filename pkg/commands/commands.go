@@ -1,82 +1,230 @@
 package command
 
-import "github.com/ppriyankuu/seiban/pkg/parser"
+import (
+	"fmt"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
 
 type Command interface {
 	Do(*parser.Data) error
 	Undo(*parser.Data) error
+	// returns what the command is about to do, see describe.go
+	Describe(*parser.Data) string
 }
 
 type CommandManager struct {
-	history          []Command
+	// the states of the boards, as a tree, see undotree.go
+	history []historyEntry
+	// the state the boards are in
 	history_position int
 	data             *parser.Data
+	// called with a description of every command executed, undone or redone
+	onChange func(description string)
+	// file the history is kept in across sessions, see journal.go
+	journalFileName string
+	// the number of lines of the journal, and the state redo goes to of every
+	// state in it as it was last written
+	journalLines int
+	journalRedo  []int
+	// called when the journal cannot be written
+	onJournalError func(err error)
+	// the commands executed since Begin, nil when there is no open transaction
+	transaction *CompositeCommand
+}
+
+// a command of the history, the board that was active when it was executed
+// and what it did, see describe.go. the entry is the state of the boards after
+// the command, a child of the state it was executed in.
+type historyEntry struct {
+	command     Command
+	boardIdx    int
+	description string
+	// the state the command was executed in, -1 for the first state
+	parent int
+	// the child state redo goes to, the last one executed or undone, 0 if none
+	redoChild int
+	// when the command was executed
+	time time.Time
 }
 
 func CreateNewCommand(data *parser.Data) *CommandManager {
 	return &CommandManager{
-		history:          []Command{CreateEmptyCommand()},
+		history:          []historyEntry{{command: CreateEmptyCommand(), parent: -1, time: time.Now()}},
 		history_position: 0,
 		data:             data,
 	}
 }
 
-func (c *CommandManager) Execute(command Command) error {
-	if len(c.history) != c.history_position+1 {
-		c.history = c.history[:c.history_position+1]
+// sets the function called with a description of every command executed,
+// undone ("undo ...") or redone ("redo ...")
+func (c *CommandManager) OnChange(onChange func(description string)) {
+	c.onChange = onChange
+}
+
+// sets the function called when the journal cannot be written. the history
+// is then kept in memory only, until the manager is dropped.
+func (c *CommandManager) OnJournalError(onJournalError func(err error)) {
+	c.onJournalError = onJournalError
+}
+
+// called after every command executed, undone or redone
+func (c *CommandManager) changed(description string) {
+	if err := c.writeJournal(); err != nil {
+		c.journalFileName = ""
+		if c.onJournalError != nil {
+			c.onJournalError(err)
+		}
+	}
+	if c.onChange != nil {
+		c.onChange(description)
 	}
+}
+
+// runs the command and puts it on the history, or in the open transaction.
+// a command of a transaction that fails rolls back the whole transaction.
+func (c *CommandManager) Execute(command Command) error {
+	description := command.Describe(c.data)
 	err := command.Do(c.data)
 	if err != nil {
+		if rollbackErr := c.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%v (and the transaction cannot be rolled back: %v)", err, rollbackErr)
+		}
 		return err
 	}
-	c.history = append(c.history, command)
-	c.history_position++
+	if len(description) == 0 {
+		// a composite command describes its steps as they run
+		description = command.Describe(c.data)
+	}
+	if c.transaction != nil {
+		c.transaction.add(command, description)
+		return nil
+	}
+	c.push(command, description)
+	return nil
+}
+
+// puts a command that was done on the history, as a new state under the
+// current one. the undone commands stay on the history, on another branch.
+func (c *CommandManager) push(command Command, description string) {
+	c.history = append(c.history, historyEntry{
+		command:     command,
+		boardIdx:    c.data.GetActiveBoardIdx(),
+		description: description,
+		parent:      c.history_position,
+		time:        time.Now(),
+	})
+	c.history_position = len(c.history) - 1
+	c.history[c.history[c.history_position].parent].redoChild = c.history_position
+	c.changed(description)
+}
+
+// starts a transaction: the commands executed until Commit are done right
+// away, but go on the history as one command that is undone and redone as a
+// unit. Rollback undoes them instead. undo and redo fail while it is open.
+func (c *CommandManager) Begin() error {
+	if c.transaction != nil {
+		return fmt.Errorf("Cannot begin a transaction: one is open already")
+	}
+	c.transaction = CreateCompositeCommand()
+	return nil
+}
+
+// ends the transaction, its commands go on the history as one command
+func (c *CommandManager) Commit() error {
+	transaction := c.transaction
+	if transaction == nil {
+		return fmt.Errorf("Cannot commit: there is no open transaction")
+	}
+	c.transaction = nil
+	switch len(transaction.commands) {
+	case 0:
+	case 1:
+		c.push(transaction.commands[0], transaction.descriptions[0])
+	default:
+		c.push(transaction, transaction.Describe(c.data))
+	}
+	return nil
+}
+
+// ends the transaction, undoing its commands in reverse order. does nothing
+// if there is no open transaction (e.g. a failing command rolled it back already).
+func (c *CommandManager) Rollback() error {
+	transaction := c.transaction
+	if transaction == nil {
+		return nil
+	}
+	c.transaction = nil
+	return undoCommands(transaction.commands, c.data)
+}
+
+// returns an error while a transaction is open, the history cannot be
+// moved through until its commands are on it
+func (c *CommandManager) checkNoTransaction(action string) error {
+	if c.transaction != nil {
+		return fmt.Errorf("Cannot %v: a transaction is open", action)
+	}
 	return nil
 }
 
+// undoes the last command, switching back to the board it was executed on
 func (c *CommandManager) Undo() error {
+	if err := c.checkNoTransaction("undo"); err != nil {
+		return err
+	}
 	if c.history_position == 0 {
 		return nil
 	}
-	err := c.history[c.history_position].Undo(c.data)
-	if err != nil {
+	entry := c.history[c.history_position]
+	if err := c.undoState(); err != nil {
 		return err
 	}
-	c.history_position--
+	c.changed("undo " + entry.description)
 	return nil
 }
 
+// redoes the last undone command, switching back to the board it was executed on
 func (c *CommandManager) Redo() error {
-	if c.history_position+1 == len(c.history) {
+	if err := c.checkNoTransaction("redo"); err != nil {
+		return err
+	}
+	redoChild := c.history[c.history_position].redoChild
+	if redoChild == 0 {
 		return nil
 	}
-	c.history_position++
-	return c.history[c.history_position].Do(c.data)
+	if err := c.redoState(redoChild); err != nil {
+		return err
+	}
+	c.changed("redo " + c.history[redoChild].description)
+	return nil
 }
 
 // ADD TASK COMMAND
 type AddTaskCommand struct {
-	listIdx   int
-	taskTitle string
-	taskDesc  string
-	taskPos   int
+	listIdx int
+	task    parser.ListItem
+	taskPos int
 }
 
-func CreateAddTaskCommand(listIdx int, taskTitle, taskDesc string, taskPos int) *AddTaskCommand {
+func CreateAddTaskCommand(listIdx int, task parser.ListItem, taskPos int) *AddTaskCommand {
 	return &AddTaskCommand{
-		listIdx:   listIdx,
-		taskTitle: taskTitle,
-		taskDesc:  taskDesc,
-		taskPos:   taskPos,
+		listIdx: listIdx,
+		task:    task,
+		taskPos: taskPos,
 	}
 }
 
 func (a *AddTaskCommand) Do(data *parser.Data) error {
-	return data.AddNewTask(a.listIdx, a.taskTitle, a.taskDesc, a.taskPos)
+	// the id is picked on the first run, so that a redo adds the same task again
+	if len(a.task.ID) == 0 {
+		a.task.ID = data.NewTaskID()
+	}
+	return data.AddNewTask(a.listIdx, a.task, a.taskPos)
 }
 
 func (a *AddTaskCommand) Undo(data *parser.Data) error {
-	if _, err := data.RemoveTask(a.listIdx, a.taskPos); err != nil {
+	if _, err := data.RemoveTaskByID(a.task.ID); err != nil {
 		return err
 	}
 	return nil
@@ -84,110 +232,630 @@ func (a *AddTaskCommand) Undo(data *parser.Data) error {
 
 // REMOVE TASK COMMAND
 type RemoveTaskCommand struct {
-	listIdx   int
-	taskTitle string
-	taskDesc  string
-	taskPos   int
+	taskID  string
+	listIdx int
+	task    parser.ListItem
+	taskPos int
 }
 
-func CreateRemoveTaskCommand(listIdx, taskPos int) *RemoveTaskCommand {
+func CreateRemoveTaskCommand(taskID string) *RemoveTaskCommand {
 	return &RemoveTaskCommand{
-		listIdx: listIdx,
-		taskPos: taskPos,
+		taskID: taskID,
 	}
 }
 
 func (r *RemoveTaskCommand) Do(data *parser.Data) error {
-	taskData, err := data.RemoveTask(r.listIdx, r.taskPos)
+	listIdx, taskPos, err := data.FindTask(r.taskID)
+	if err != nil {
+		return err
+	}
+	taskData, err := data.RemoveTask(listIdx, taskPos)
 	if err != nil {
 		return err
 	}
-	r.taskTitle = taskData.ItemName
-	r.taskDesc = taskData.ItemDescription
+	r.listIdx = listIdx
+	r.taskPos = taskPos
+	r.task = taskData
 	return nil
 }
 
 func (r *RemoveTaskCommand) Undo(data *parser.Data) error {
-	return data.AddNewTask(r.listIdx, r.taskTitle, r.taskDesc, r.taskPos)
+	return data.AddNewTask(r.listIdx, r.task, r.taskPos)
 }
 
 // SWAP LIST ITEM COMMAND
 type SwapListItemCommand struct {
-	listIdx       int
-	taskIdxFirst  int
-	taskIdxSecond int
+	taskIDFirst  string
+	taskIDSecond string
 }
 
-func CreateSwapListItemCommand(listIdx, taskIdxFirst, taskIdxSecond int) *SwapListItemCommand {
+func CreateSwapListItemCommand(taskIDFirst, taskIDSecond string) *SwapListItemCommand {
 	return &SwapListItemCommand{
-		listIdx:       listIdx,
-		taskIdxFirst:  taskIdxFirst,
-		taskIdxSecond: taskIdxSecond,
+		taskIDFirst:  taskIDFirst,
+		taskIDSecond: taskIDSecond,
 	}
 }
 
 func (s *SwapListItemCommand) Do(data *parser.Data) error {
-	return data.SwapListItems(s.listIdx, s.taskIdxFirst, s.taskIdxSecond)
+	return data.SwapTasksByID(s.taskIDFirst, s.taskIDSecond)
 }
 
 func (s *SwapListItemCommand) Undo(data *parser.Data) error {
-	return data.SwapListItems(s.listIdx, s.taskIdxSecond, s.taskIdxFirst)
+	return data.SwapTasksByID(s.taskIDSecond, s.taskIDFirst)
 }
 
 // MOVE TASK COMMAND
 type MoveTaskCommand struct {
+	taskID      string
 	prevTaskIdx int
 	prevListIdx int
 	newListIdx  int
 }
 
-func CreateMoveTaskCommand(prevTaskIdx, prevListIdx, newListIdx int) *MoveTaskCommand {
+func CreateMoveTaskCommand(taskID string, newListIdx int) *MoveTaskCommand {
 	return &MoveTaskCommand{
-		prevTaskIdx: prevTaskIdx,
-		prevListIdx: prevListIdx,
-		newListIdx:  newListIdx,
+		taskID:     taskID,
+		newListIdx: newListIdx,
 	}
 }
 
+// moves the task to the end of the new list
 func (s *MoveTaskCommand) Do(data *parser.Data) error {
-	return data.MoveTask(s.prevTaskIdx, s.prevListIdx, s.newListIdx)
+	prevListIdx, prevTaskIdx, err := data.FindTask(s.taskID)
+	if err != nil {
+		return err
+	}
+	s.prevListIdx = prevListIdx
+	s.prevTaskIdx = prevTaskIdx
+	newTaskIdx, err := data.GetTaskCount(s.newListIdx)
+	if err != nil {
+		return err
+	}
+	if prevListIdx == s.newListIdx {
+		newTaskIdx--
+	}
+	return data.MoveTaskByID(s.taskID, s.newListIdx, newTaskIdx)
 }
 
 func (s *MoveTaskCommand) Undo(data *parser.Data) error {
-	return data.MoveTask(s.prevTaskIdx, s.newListIdx, s.prevListIdx)
+	return data.MoveTaskByID(s.taskID, s.prevListIdx, s.prevTaskIdx)
+}
+
+// SET TASK DONE COMMAND
+type SetTaskDoneCommand struct {
+	taskID  string
+	done    bool
+	wasDone bool
+	// the day the task was done after and before the command, so that undo
+	// and redo give it back the same day
+	doneDate, prevDoneDate time.Time
+}
+
+func CreateSetTaskDoneCommand(taskID string, done bool) *SetTaskDoneCommand {
+	return &SetTaskDoneCommand{
+		taskID: taskID,
+		done:   done,
+	}
+}
+
+func (s *SetTaskDoneCommand) Do(data *parser.Data) error {
+	task, err := data.GetTaskByID(s.taskID)
+	if err != nil {
+		return err
+	}
+	s.wasDone, s.prevDoneDate = task.Done, task.DoneDate
+	if !s.doneDate.IsZero() {
+		// redone
+		return data.RestoreTaskDone(s.taskID, s.done, s.doneDate)
+	}
+	if err := data.SetTaskDoneByID(s.taskID, s.done); err != nil {
+		return err
+	}
+	task, err = data.GetTaskByID(s.taskID)
+	if err != nil {
+		return err
+	}
+	s.doneDate = task.DoneDate
+	return nil
+}
+
+func (s *SetTaskDoneCommand) Undo(data *parser.Data) error {
+	return data.RestoreTaskDone(s.taskID, s.wasDone, s.prevDoneDate)
+}
+
+// COMPLETE TASK COMMAND
+// moves the task to the done list and marks it as done, undone as one step
+type CompleteTaskCommand struct {
+	steps       *CompositeCommand
+	taskID      string
+	doneListIdx int
+}
+
+func CreateCompleteTaskCommand(taskID string, doneListIdx int) *CompleteTaskCommand {
+	return &CompleteTaskCommand{
+		steps:       CreateCompositeCommand(CreateMoveTaskCommand(taskID, doneListIdx), CreateSetTaskDoneCommand(taskID, true)),
+		taskID:      taskID,
+		doneListIdx: doneListIdx,
+	}
+}
+
+func (c *CompleteTaskCommand) Do(data *parser.Data) error {
+	return c.steps.Do(data)
+}
+
+func (c *CompleteTaskCommand) Undo(data *parser.Data) error {
+	return c.steps.Undo(data)
+}
+
+// MOVE TASK TO BOARD COMMAND
+type MoveTaskToBoardCommand struct {
+	taskID       string
+	prevBoardIdx int
+	prevListIdx  int
+	prevTaskIdx  int
+	newBoardIdx  int
+	newListIdx   int
+	// the task with the day it was done, if the move marked it as not done
+	undone []doneTask
+}
+
+func CreateMoveTaskToBoardCommand(taskID string, newBoardIdx, newListIdx int) *MoveTaskToBoardCommand {
+	return &MoveTaskToBoardCommand{
+		taskID:      taskID,
+		newBoardIdx: newBoardIdx,
+		newListIdx:  newListIdx,
+	}
+}
+
+// moves a task of the active board to the end of a list of another board
+func (m *MoveTaskToBoardCommand) Do(data *parser.Data) error {
+	prevListIdx, prevTaskIdx, err := data.FindTask(m.taskID)
+	if err != nil {
+		return err
+	}
+	m.prevBoardIdx = data.GetActiveBoardIdx()
+	m.prevListIdx = prevListIdx
+	m.prevTaskIdx = prevTaskIdx
+	newTaskIdx, err := data.GetBoardTaskCount(m.newBoardIdx, m.newListIdx)
+	if err != nil {
+		return err
+	}
+	if m.prevBoardIdx == m.newBoardIdx && m.prevListIdx == m.newListIdx {
+		newTaskIdx--
+	}
+	undone, err := data.MoveTaskToBoard(m.taskID, m.newBoardIdx, m.newListIdx, newTaskIdx)
+	m.undone = doneTasks(undone)
+	return err
+}
+
+func (m *MoveTaskToBoardCommand) Undo(data *parser.Data) error {
+	if _, err := data.MoveTaskToBoard(m.taskID, m.prevBoardIdx, m.prevListIdx, m.prevTaskIdx); err != nil {
+		return err
+	}
+	return restoreDone(data, m.undone)
 }
 
 // EDIT TASK COMMAND
 type EditTaskCommand struct {
-	listIdx           int
-	taskIdx           int
-	taskTitle         string
-	taskDesc          string
-	originalTaskTitle string
-	originalTaskDesc  string
+	taskID       string
+	task         parser.ListItem
+	originalTask parser.ListItem
 }
 
-func CreateEditTaskCommand(listIdx, taskIdx int, taskTitle, taskDesc string) *EditTaskCommand {
+func CreateEditTaskCommand(taskID string, task parser.ListItem) *EditTaskCommand {
 	return &EditTaskCommand{
-		listIdx:   listIdx,
-		taskIdx:   taskIdx,
-		taskTitle: taskTitle,
-		taskDesc:  taskDesc,
+		taskID: taskID,
+		task:   task,
 	}
 }
 
 func (e *EditTaskCommand) Do(data *parser.Data) error {
-	originalTask, err := data.GetTask(e.listIdx, e.taskIdx)
+	originalTask, err := data.GetTaskByID(e.taskID)
 	if err != nil {
 		return err
 	}
-	e.originalTaskTitle = originalTask.ItemName
-	e.originalTaskDesc = originalTask.ItemDescription
-	return data.EditTask(e.listIdx, e.taskIdx, e.taskTitle, e.taskDesc)
+	e.originalTask = *originalTask
+	if err := data.EditTaskByID(e.taskID, e.task); err != nil {
+		return err
+	}
+	// redone as it was done, on the same day if the edit marked the task as done
+	editedTask, err := data.GetTaskByID(e.taskID)
+	if err != nil {
+		return err
+	}
+	e.task.DoneDate = editedTask.DoneDate
+	return nil
 }
 
 func (e *EditTaskCommand) Undo(data *parser.Data) error {
-	return data.EditTask(e.listIdx, e.taskIdx, e.originalTaskTitle, e.originalTaskDesc)
+	return data.EditTaskByID(e.taskID, e.originalTask)
+}
+
+// ADD SUBTASK COMMAND
+type AddSubtaskCommand struct {
+	taskID     string
+	subtask    parser.Subtask
+	subtaskIdx int
+}
+
+func CreateAddSubtaskCommand(taskID string, subtaskName string, subtaskIdx int) *AddSubtaskCommand {
+	return &AddSubtaskCommand{
+		taskID:     taskID,
+		subtask:    parser.Subtask{Name: subtaskName},
+		subtaskIdx: subtaskIdx,
+	}
+}
+
+func (a *AddSubtaskCommand) Do(data *parser.Data) error {
+	return data.AddSubtask(a.taskID, a.subtask, a.subtaskIdx)
+}
+
+func (a *AddSubtaskCommand) Undo(data *parser.Data) error {
+	_, err := data.RemoveSubtask(a.taskID, a.subtaskIdx)
+	return err
+}
+
+// REMOVE SUBTASK COMMAND
+type RemoveSubtaskCommand struct {
+	taskID     string
+	subtask    parser.Subtask
+	subtaskIdx int
+}
+
+func CreateRemoveSubtaskCommand(taskID string, subtaskIdx int) *RemoveSubtaskCommand {
+	return &RemoveSubtaskCommand{
+		taskID:     taskID,
+		subtaskIdx: subtaskIdx,
+	}
+}
+
+func (r *RemoveSubtaskCommand) Do(data *parser.Data) error {
+	subtask, err := data.RemoveSubtask(r.taskID, r.subtaskIdx)
+	if err != nil {
+		return err
+	}
+	r.subtask = subtask
+	return nil
+}
+
+func (r *RemoveSubtaskCommand) Undo(data *parser.Data) error {
+	return data.AddSubtask(r.taskID, r.subtask, r.subtaskIdx)
+}
+
+// TOGGLE SUBTASK COMMAND
+type ToggleSubtaskCommand struct {
+	taskID     string
+	subtaskIdx int
+}
+
+func CreateToggleSubtaskCommand(taskID string, subtaskIdx int) *ToggleSubtaskCommand {
+	return &ToggleSubtaskCommand{
+		taskID:     taskID,
+		subtaskIdx: subtaskIdx,
+	}
+}
+
+func (t *ToggleSubtaskCommand) Do(data *parser.Data) error {
+	return data.ToggleSubtask(t.taskID, t.subtaskIdx)
+}
+
+func (t *ToggleSubtaskCommand) Undo(data *parser.Data) error {
+	return data.ToggleSubtask(t.taskID, t.subtaskIdx)
+}
+
+// SWAP SUBTASK COMMAND
+type SwapSubtaskCommand struct {
+	taskID           string
+	subtaskIdxFirst  int
+	subtaskIdxSecond int
+}
+
+func CreateSwapSubtaskCommand(taskID string, subtaskIdxFirst, subtaskIdxSecond int) *SwapSubtaskCommand {
+	return &SwapSubtaskCommand{
+		taskID:           taskID,
+		subtaskIdxFirst:  subtaskIdxFirst,
+		subtaskIdxSecond: subtaskIdxSecond,
+	}
+}
+
+func (s *SwapSubtaskCommand) Do(data *parser.Data) error {
+	return data.SwapSubtasks(s.taskID, s.subtaskIdxFirst, s.subtaskIdxSecond)
+}
+
+func (s *SwapSubtaskCommand) Undo(data *parser.Data) error {
+	return data.SwapSubtasks(s.taskID, s.subtaskIdxSecond, s.subtaskIdxFirst)
+}
+
+// ADD LIST COMMAND
+type AddListCommand struct {
+	listIdx   int
+	listTitle string
+}
+
+func CreateAddListCommand(listIdx int, listTitle string) *AddListCommand {
+	return &AddListCommand{
+		listIdx:   listIdx,
+		listTitle: listTitle,
+	}
+}
+
+func (a *AddListCommand) Do(data *parser.Data) error {
+	return data.AddList(a.listIdx, a.listTitle)
+}
+
+func (a *AddListCommand) Undo(data *parser.Data) error {
+	_, err := data.RemoveList(a.listIdx, -1)
+	return err
+}
+
+// RENAME LIST COMMAND
+type RenameListCommand struct {
+	listIdx   int
+	listTitle string
+	prevTitle string
+	// the front matter before the rename, its done list and wip limits are renamed with the list
+	prevFrontMatter []string
+}
+
+func CreateRenameListCommand(listIdx int, listTitle string) *RenameListCommand {
+	return &RenameListCommand{
+		listIdx:   listIdx,
+		listTitle: listTitle,
+	}
+}
+
+func (r *RenameListCommand) Do(data *parser.Data) error {
+	list, err := data.GetList(r.listIdx)
+	if err != nil {
+		return err
+	}
+	r.prevTitle = list.Title()
+	r.prevFrontMatter = data.FrontMatter()
+	return data.RenameList(r.listIdx, r.listTitle)
+}
+
+func (r *RenameListCommand) Undo(data *parser.Data) error {
+	return data.RestoreListTitle(r.listIdx, r.prevTitle, r.prevFrontMatter)
+}
+
+// REMOVE LIST COMMAND
+// removes a list, its tasks are moved to another list or removed with it
+type RemoveListCommand struct {
+	listIdx int
+	// list the tasks are moved to, -1 to remove them with the list
+	destListIdx int
+	list        parser.List
+	// the front matter before the removal, its done list and wip limit of the list are removed with it
+	prevFrontMatter []string
+}
+
+func CreateRemoveListCommand(listIdx, destListIdx int) *RemoveListCommand {
+	return &RemoveListCommand{
+		listIdx:     listIdx,
+		destListIdx: destListIdx,
+	}
+}
+
+func (r *RemoveListCommand) Do(data *parser.Data) error {
+	r.prevFrontMatter = data.FrontMatter()
+	list, err := data.RemoveList(r.listIdx, r.destListIdx)
+	if err != nil {
+		return err
+	}
+	r.list = list
+	return nil
+}
+
+func (r *RemoveListCommand) Undo(data *parser.Data) error {
+	return data.RestoreList(r.listIdx, r.list, r.destListIdx, r.prevFrontMatter)
+}
+
+// MOVE LIST COMMAND
+type MoveListCommand struct {
+	listIdx    int
+	newListIdx int
+	// the tasks of the done list marked as not done by the move, when it
+	// stopped being the done list, with the day they were done
+	undone []doneTask
+}
+
+// a task marked as not done by a command, and the day it was done
+type doneTask struct {
+	taskID   string
+	doneDate time.Time
+}
+
+// returns the ids and the days of the tasks that were done
+func doneTasks(tasks []parser.ListItem) []doneTask {
+	var done []doneTask
+	for _, task := range tasks {
+		done = append(done, doneTask{task.ID, task.DoneDate})
+	}
+	return done
+}
+
+// marks the tasks as done again on the day they were done
+func restoreDone(data *parser.Data, tasks []doneTask) error {
+	for _, task := range tasks {
+		if err := data.RestoreTaskDone(task.taskID, true, task.doneDate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CreateMoveListCommand(listIdx, newListIdx int) *MoveListCommand {
+	return &MoveListCommand{
+		listIdx:    listIdx,
+		newListIdx: newListIdx,
+	}
+}
+
+func (m *MoveListCommand) Do(data *parser.Data) error {
+	undone, err := data.MoveList(m.listIdx, m.newListIdx)
+	m.undone = doneTasks(undone)
+	return err
+}
+
+func (m *MoveListCommand) Undo(data *parser.Data) error {
+	// the list that became the done list stops being it again
+	undone, err := data.MoveList(m.newListIdx, m.listIdx)
+	if err != nil {
+		return err
+	}
+	if err := restoreDone(data, doneTasks(undone)); err != nil {
+		return err
+	}
+	return restoreDone(data, m.undone)
+}
+
+// RESTORE BACKUP COMMAND
+// replaces the boards by the ones of a backup of the board file
+type RestoreBackupCommand struct {
+	// the lines of the backup
+	content []string
+	// when the backup was written
+	backupTime  time.Time
+	prevContent []string
+}
+
+func CreateRestoreBackupCommand(content []string, backupTime time.Time) *RestoreBackupCommand {
+	return &RestoreBackupCommand{
+		content:    content,
+		backupTime: backupTime,
+	}
+}
+
+func (r *RestoreBackupCommand) Do(data *parser.Data) error {
+	r.prevContent = data.Content()
+	return data.ReplaceContent(r.content)
+}
+
+func (r *RestoreBackupCommand) Undo(data *parser.Data) error {
+	return data.ReplaceContent(r.prevContent)
+}
+
+// ARCHIVE COMMAND
+// moves the tasks of the done lists to the archive file, see parser.Archive.
+// the archive file is written right away, the boards are saved too.
+type ArchiveCommand struct {
+	// tasks done before this day are archived, all of them if it is zero
+	doneBefore  time.Time
+	archived    []parser.ArchivedTask
+	prevContent []string
+}
+
+func CreateArchiveCommand(doneBefore time.Time) *ArchiveCommand {
+	return &ArchiveCommand{
+		doneBefore: doneBefore,
+	}
+}
+
+func (a *ArchiveCommand) Do(data *parser.Data) error {
+	prevContent := data.Content()
+	archived, err := data.Archive(a.doneBefore)
+	if err != nil {
+		return err
+	}
+	a.archived, a.prevContent = archived, prevContent
+	return nil
+}
+
+func (a *ArchiveCommand) Undo(data *parser.Data) error {
+	return data.RestoreArchived(a.prevContent, a.archived)
+}
+
+// UNARCHIVE TASK COMMAND
+// moves a task of the archive file back to the boards, see parser.Unarchive.
+// the archive file is written right away, the boards are saved too.
+type UnarchiveTaskCommand struct {
+	archivedTask parser.ArchivedTask
+	// the id the task has on the boards
+	taskID string
+}
+
+func CreateUnarchiveTaskCommand(archivedTask parser.ArchivedTask) *UnarchiveTaskCommand {
+	return &UnarchiveTaskCommand{
+		archivedTask: archivedTask,
+	}
+}
+
+func (u *UnarchiveTaskCommand) Do(data *parser.Data) error {
+	taskID, err := data.Unarchive(u.archivedTask)
+	if err != nil {
+		return err
+	}
+	u.taskID = taskID
+	return nil
+}
+
+func (u *UnarchiveTaskCommand) Undo(data *parser.Data) error {
+	return data.Rearchive(u.taskID, u.archivedTask)
+}
+
+// COMPOSITE COMMAND
+// runs several commands as one: they are done in order and undone in
+// reverse order, and a failing step leaves the data as it was
+type CompositeCommand struct {
+	commands []Command
+	// what the commands did, see describe.go
+	descriptions []string
+}
+
+func CreateCompositeCommand(commands ...Command) *CompositeCommand {
+	return &CompositeCommand{
+		commands: commands,
+	}
+}
+
+// runs the commands in order. if one of them fails, the ones before it are undone.
+func (c *CompositeCommand) Do(data *parser.Data) error {
+	var descriptions []string
+	for idx, command := range c.commands {
+		descriptions = append(descriptions, command.Describe(data))
+		if err := command.Do(data); err != nil {
+			if undoErr := undoCommands(c.commands[:idx], data); undoErr != nil {
+				return fmt.Errorf("%v (and the steps before it cannot be undone: %v)", err, undoErr)
+			}
+			return err
+		}
+	}
+	if len(c.descriptions) == 0 {
+		// described on the first run, a redo finds the tasks elsewhere
+		c.descriptions = descriptions
+	}
+	return nil
+}
+
+// undoes the commands in reverse order. if one of them fails, the ones after it are done again.
+func (c *CompositeCommand) Undo(data *parser.Data) error {
+	for idx := len(c.commands) - 1; idx >= 0; idx-- {
+		if err := c.commands[idx].Undo(data); err != nil {
+			for _, command := range c.commands[idx+1:] {
+				if doErr := command.Do(data); doErr != nil {
+					return fmt.Errorf("%v (and the steps after it cannot be done again: %v)", err, doErr)
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// adds a command that was done already, for a transaction
+func (c *CompositeCommand) add(command Command, description string) {
+	c.commands = append(c.commands, command)
+	c.descriptions = append(c.descriptions, description)
+}
+
+// undoes the commands, the last one first
+func undoCommands(commands []Command, data *parser.Data) error {
+	for idx := len(commands) - 1; idx >= 0; idx-- {
+		if err := commands[idx].Undo(data); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // EMPTY COMMAND
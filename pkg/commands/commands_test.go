@@ -0,0 +1,542 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
+
+const board = "# b\n\n## TODO\n\t- [ ] one <!-- id:a1 -->\n\t- [ ] two <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n"
+
+// loads the board from a MemoryStorage, returns the data and a function
+// returning the text of the board as Save writes it
+func loadBoard(t *testing.T, text string) (*parser.Data, func() string) {
+	t.Helper()
+	storage := parser.NewMemoryStorage(strings.Split(text, "\n"))
+	data := &parser.Data{}
+	data.SetStorage(storage)
+	if err := data.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return data, func() string {
+		return strings.Join(data.Content(), "\n")
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	tests := []struct {
+		name    string
+		command Command
+	}{
+		{"add task", CreateAddTaskCommand(1, parser.ListItem{ItemName: "three"}, 0)},
+		{"remove task", CreateRemoveTaskCommand("a1")},
+		{"swap tasks", CreateSwapListItemCommand("a1", "b2")},
+		{"move task", CreateMoveTaskCommand("a1", 1)},
+		{"set task done", CreateSetTaskDoneCommand("b2", true)},
+		{"complete task", CreateCompleteTaskCommand("a1", 2)},
+		{"edit task", CreateEditTaskCommand("a1", parser.ListItem{ItemName: "one edited", Tags: []string{"bug"}})},
+		{"add subtask", CreateAddSubtaskCommand("a1", "step", 0)},
+		{"add list", CreateAddListCommand(1, "REVIEW")},
+		{"rename list", CreateRenameListCommand(2, "FINISHED")},
+		{"remove list", CreateRemoveListCommand(0, 1)},
+		{"move list", CreateMoveListCommand(0, 2)},
+		{"restore backup", CreateRestoreBackupCommand(strings.Split("# b\n\n## TODO\n\t- [ ] three <!-- id:c3 -->\n\n", "\n"), time.Now())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, content := loadBoard(t, board)
+			manager := CreateNewCommand(data)
+			if err := manager.Execute(tt.command); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			done := content()
+			if done == board {
+				t.Fatal("the command did not change the board")
+			}
+			if err := manager.Undo(); err != nil {
+				t.Fatalf("Undo: %v", err)
+			}
+			if got := content(); got != board {
+				t.Errorf("after undo:\n%q\nwant:\n%q", got, board)
+			}
+			if err := manager.Redo(); err != nil {
+				t.Fatalf("Redo: %v", err)
+			}
+			if got := content(); got != done {
+				t.Errorf("after redo:\n%q\nwant:\n%q", got, done)
+			}
+		})
+	}
+}
+
+func TestUndoTree(t *testing.T) {
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	moved := content()
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	// starts a new branch, the move stays on the old one
+	if err := manager.Execute(CreateRemoveTaskCommand("b2")); err != nil {
+		t.Fatal(err)
+	}
+	removed := content()
+	if len(manager.States()) != 3 {
+		t.Errorf("%v states, want the first one and one for each command", len(manager.States()))
+	}
+	if err := manager.Older(); err != nil {
+		t.Fatal(err)
+	}
+	// the state made before the removal, on the old branch
+	if got := content(); got != moved {
+		t.Errorf("on the old branch:\n%q\nwant:\n%q", got, moved)
+	}
+	if err := manager.Older(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != board {
+		t.Errorf("in the first state:\n%q\nwant:\n%q", got, board)
+	}
+	if err := manager.GoTo(len(manager.States()) - 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != removed {
+		t.Errorf("in the last state:\n%q\nwant:\n%q", got, removed)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	if err := manager.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Begin(); err == nil {
+		t.Error("Begin succeeded with a transaction open")
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("b2", 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Undo(); err == nil {
+		t.Error("Undo succeeded with a transaction open")
+	}
+	if err := manager.Redo(); err == nil {
+		t.Error("Redo succeeded with a transaction open")
+	}
+	if err := manager.GoTo(0); err == nil {
+		t.Error("GoTo succeeded with a transaction open")
+	}
+	if err := manager.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	done := content()
+	if len(manager.States()) != 2 {
+		t.Errorf("%v states, want the first one and the transaction", len(manager.States()))
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != board {
+		t.Errorf("after undo:\n%q\nwant:\n%q", got, board)
+	}
+	if err := manager.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != done {
+		t.Errorf("after redo:\n%q\nwant:\n%q", got, done)
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	if err := manager.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateRemoveTaskCommand("missing")); err == nil {
+		t.Fatal("removing a missing task succeeded")
+	}
+	if got := content(); got != board {
+		t.Errorf("after the failed transaction:\n%q\nwant:\n%q", got, board)
+	}
+	if err := manager.Commit(); err == nil {
+		t.Error("Commit succeeded after the transaction was rolled back")
+	}
+	if len(manager.States()) != 1 {
+		t.Errorf("%v states, want only the first one", len(manager.States()))
+	}
+}
+
+func TestCompositeCommand(t *testing.T) {
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	failing := CreateCompositeCommand(CreateMoveTaskCommand("a1", 1), CreateRemoveTaskCommand("missing"))
+	if err := manager.Execute(failing); err == nil {
+		t.Fatal("a composite command with a failing step succeeded")
+	}
+	if got := content(); got != board {
+		t.Errorf("after the failed command:\n%q\nwant:\n%q", got, board)
+	}
+	if err := manager.Execute(CreateCompositeCommand(CreateMoveTaskCommand("a1", 1), CreateMoveTaskCommand("b2", 1))); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != board {
+		t.Errorf("after undo:\n%q\nwant:\n%q", got, board)
+	}
+}
+
+func TestJournal(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "seiban.md")
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	if err := manager.UseJournal(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("b2", 2)); err != nil {
+		t.Fatal(err)
+	}
+	moved := content()
+
+	// a new session on the board as it was left
+	data, content = loadBoard(t, moved)
+	manager = CreateNewCommand(data)
+	if err := manager.UseJournal(fileName); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := manager.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := content(); got != board {
+		t.Errorf("after undoing the journal:\n%q\nwant:\n%q", got, board)
+	}
+
+	// a session on a board changed since, the journal is not used
+	changed := strings.Replace(board, "one", "one edited", 1)
+	data, content = loadBoard(t, changed)
+	manager = CreateNewCommand(data)
+	if err := manager.UseJournal(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != changed {
+		t.Errorf("after undo:\n%q\nwant:\n%q", got, changed)
+	}
+	journalFileName := filepath.Join(filepath.Dir(fileName), ".seiban", "seiban.md.history.jsonl")
+	if _, err := os.Stat(journalFileName); !os.IsNotExist(err) {
+		t.Errorf("the journal of other boards was kept: %v", err)
+	}
+}
+
+func TestJournalAppends(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "seiban.md")
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	if err := manager.UseJournal(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	journal, _ := os.ReadFile(manager.journalFileName)
+	// a branch: undo, then another command
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("b2", 2)); err != nil {
+		t.Fatal(err)
+	}
+	appended, _ := os.ReadFile(manager.journalFileName)
+	if !strings.HasPrefix(string(appended), string(journal)) {
+		t.Errorf("the journal was written again:\n%s", appended)
+	}
+
+	data, content = loadBoard(t, content())
+	manager = CreateNewCommand(data)
+	if err := manager.UseJournal(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if len(manager.history) != 3 || manager.history_position != 2 {
+		t.Fatalf("history of %v states at %v, want 3 at 2", len(manager.history), manager.history_position)
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != board {
+		t.Errorf("after undo:\n%q\nwant:\n%q", got, board)
+	}
+}
+
+func TestJournalCompacts(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "seiban.md")
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	if err := manager.UseJournal(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	for range journalSlack {
+		if err := manager.Undo(); err != nil {
+			t.Fatal(err)
+		}
+		if err := manager.Redo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	journal, _ := os.ReadFile(manager.journalFileName)
+	if lines := strings.Count(string(journal), "\n"); lines > 2*len(manager.history)+journalSlack {
+		t.Errorf("the journal holds %v lines for %v states", lines, len(manager.history))
+	}
+
+	data, content = loadBoard(t, content())
+	manager = CreateNewCommand(data)
+	if err := manager.UseJournal(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != board {
+		t.Errorf("after undo:\n%q\nwant:\n%q", got, board)
+	}
+}
+
+func TestJournalError(t *testing.T) {
+	dir := t.TempDir()
+	// the journal cannot be written where a file is in the way
+	if err := os.WriteFile(filepath.Join(dir, ".seiban"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, content := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	if err := manager.UseJournal(filepath.Join(dir, "seiban.md")); err != nil {
+		t.Fatal(err)
+	}
+	var journalErrors []error
+	manager.OnJournalError(func(err error) {
+		journalErrors = append(journalErrors, err)
+	})
+	for _, taskID := range []string{"a1", "b2"} {
+		if err := manager.Execute(CreateMoveTaskCommand(taskID, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(journalErrors) != 1 {
+		t.Errorf("journal errors = %v, want the first one", journalErrors)
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); !strings.Contains(got, "## DOING\n\t- [ ] one <!-- id:a1 -->\n\n") {
+		t.Errorf("after undo:\n%q", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	data, _ := loadBoard(t, board)
+	manager := CreateNewCommand(data)
+	var descriptions []string
+	manager.OnChange(func(description string) {
+		descriptions = append(descriptions, description)
+	})
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateEditTaskCommand("b2", parser.ListItem{ItemName: "two edited"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"move 'one' TODO → DOING", "rename 'two' → 'two edited'", "undo rename 'two' → 'two edited'"}
+	if strings.Join(descriptions, "\n") != strings.Join(want, "\n") {
+		t.Errorf("descriptions = %q, want %q", descriptions, want)
+	}
+}
+
+func TestUndoKeepsDoneDay(t *testing.T) {
+	doneBoard := "# b\n\n## TODO\n\n\n## DONE\n\t- [x] one @done(2020-01-02) <!-- id:a1 -->\n\n"
+	data, content := loadBoard(t, doneBoard)
+	manager := CreateNewCommand(data)
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Execute(CreateSetTaskDoneCommand("a1", false)); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := manager.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := content(); got != doneBoard {
+		t.Errorf("after undo:\n%q\nwant:\n%q", got, doneBoard)
+	}
+}
+
+func TestArchiveUndo(t *testing.T) {
+	t.Chdir(t.TempDir())
+	data := &parser.Data{}
+	data.SetFileName("seiban.md")
+	if err := data.ParseData(strings.Split("# b\n\n## TODO\n\n\n## DONE\n\t- [x] one @done(2020-01-02) <!-- id:a1 -->\n\n", "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.Save(); err != nil {
+		t.Fatal(err)
+	}
+	archivedCount := func() int {
+		archive, err := data.OpenArchive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(archive.ArchivedTasks())
+	}
+	before := strings.Join(data.Content(), "\n")
+	manager := CreateNewCommand(data)
+	if err := manager.Execute(CreateArchiveCommand(time.Time{})); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := data.GetTaskCount(1); count != 0 || archivedCount() != 1 {
+		t.Fatalf("%v tasks in the done list and %v archived after archiving", count, archivedCount())
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(data.Content(), "\n"); got != before || archivedCount() != 0 {
+		t.Errorf("after undo, %v archived and the board:\n%q\nwant:\n%q", archivedCount(), got, before)
+	}
+
+	if err := manager.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	archive, _ := data.OpenArchive()
+	if err := manager.Execute(CreateUnarchiveTaskCommand(archive.ArchivedTasks()[0])); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := data.GetTaskCount(1); count != 1 || archivedCount() != 0 {
+		t.Fatalf("%v tasks in the done list and %v archived after unarchiving", count, archivedCount())
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := data.GetTaskCount(1); count != 0 || archivedCount() != 1 {
+		t.Errorf("%v tasks in the done list and %v archived after undoing the unarchive", count, archivedCount())
+	}
+}
+
+func TestCommandAfterFileChanged(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("seiban.md", []byte(board), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data := &parser.Data{}
+	data.SetFileName("seiban.md")
+	if err := data.Load(); err != nil {
+		t.Fatal(err)
+	}
+	// another program adds a task, then a command runs on the board
+	changed := strings.Replace(board, "## DOING\n", "## DOING\n\t- [ ] three <!-- id:c3 -->\n", 1)
+	if err := os.WriteFile("seiban.md", []byte(changed), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := CreateNewCommand(data)
+	if err := manager.Execute(CreateMoveTaskCommand("a1", 2)); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := os.ReadFile("seiban.md"); string(saved) != changed {
+		t.Fatal("the command saved the board")
+	}
+	conflicts, err := data.SaveMerged()
+	if err != nil || len(conflicts) > 0 {
+		t.Fatalf("SaveMerged() = %v, %v", conflicts, err)
+	}
+	saved, _ := os.ReadFile("seiban.md")
+	if !strings.Contains(string(saved), "three <!-- id:c3 -->") || !strings.Contains(string(saved), "## DONE\n\t- [ ] one <!-- id:a1 -->") {
+		t.Errorf("saved as\n%v", string(saved))
+	}
+	if fileChanged, err := data.FileChanged(); err != nil || fileChanged || data.HasUnsavedChanges() {
+		t.Errorf("FileChanged() = %v, %v and HasUnsavedChanges() = %v after SaveMerged", fileChanged, err, data.HasUnsavedChanges())
+	}
+}
+
+func TestListCommandsClearDone(t *testing.T) {
+	const doneBoard = "# b\n\n## TODO\n\t- [ ] one <!-- id:a1 -->\n\n\n## DOING\n\n\n## DONE\n\t- [x] two @done(2026-10-01) <!-- id:b2 -->\n\n"
+	tests := []struct {
+		name    string
+		command Command
+		// whether the task of the done list is still done
+		done bool
+	}{
+		{"remove the done list", CreateRemoveListCommand(2, 0), false},
+		{"remove the done list into the new done list", CreateRemoveListCommand(2, 1), true},
+		{"move the done list", CreateMoveListCommand(2, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, content := loadBoard(t, doneBoard)
+			manager := CreateNewCommand(data)
+			if err := manager.Execute(tt.command); err != nil {
+				t.Fatal(err)
+			}
+			task, _ := data.GetTaskByID("b2")
+			if task.Done != tt.done || task.DoneDate.IsZero() == tt.done {
+				t.Errorf("task is done %v on %v, want done %v", task.Done, task.DoneDate, tt.done)
+			}
+			if err := manager.Undo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := content(); got != doneBoard {
+				t.Errorf("after undo:\n%q\nwant:\n%q", got, doneBoard)
+			}
+		})
+	}
+}
+
+func TestMoveTaskToBoardClearsDone(t *testing.T) {
+	const boards = "# work\n\n## TODO\n\n\n## DONE\n\t- [x] one @done(2026-10-01) <!-- id:a1 -->\n\n\n# home\n\n## TODO\n\n\n## DONE\n\n"
+	data, content := loadBoard(t, boards)
+	manager := CreateNewCommand(data)
+	if err := manager.Execute(CreateMoveTaskToBoardCommand("a1", 1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); !strings.Contains(got, "# home\n\n## TODO\n\t- [ ] one <!-- id:a1 -->") {
+		t.Errorf("moved to the other board as:\n%q", got)
+	}
+	if err := manager.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != boards {
+		t.Errorf("after undo:\n%q\nwant:\n%q", got, boards)
+	}
+	// to the done list of the other board the task stays done
+	if err := manager.Execute(CreateMoveTaskToBoardCommand("a1", 1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); !strings.Contains(got, "# home\n\n## TODO\n\n\n## DONE\n\t- [x] one @done(2026-10-01) <!-- id:a1 -->\n") {
+		t.Errorf("moved to the other done list as:\n%q", got)
+	}
+}
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
+
+// the descriptions of the commands, e.g. "move 'fix login' TODO → DOING".
+// a command is described before it runs, while the tasks and lists it
+// refers to are still where they were, except for a CompositeCommand, whose
+// steps are described as they run.
+
+func (a *AddTaskCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("add %v to %v", quote(a.task.ItemName), listName(data, a.listIdx))
+}
+
+func (r *RemoveTaskCommand) Describe(data *parser.Data) string {
+	listIdx, _, _ := data.FindTask(r.taskID)
+	return fmt.Sprintf("delete %v from %v", taskName(data, r.taskID), listName(data, listIdx))
+}
+
+func (s *SwapListItemCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("swap %v and %v", taskName(data, s.taskIDFirst), taskName(data, s.taskIDSecond))
+}
+
+func (s *MoveTaskCommand) Describe(data *parser.Data) string {
+	listIdx, _, _ := data.FindTask(s.taskID)
+	return fmt.Sprintf("move %v %v → %v", taskName(data, s.taskID), listName(data, listIdx), listName(data, s.newListIdx))
+}
+
+func (c *CompleteTaskCommand) Describe(data *parser.Data) string {
+	listIdx, _, _ := data.FindTask(c.taskID)
+	if listIdx == c.doneListIdx {
+		return fmt.Sprintf("mark %v done", taskName(data, c.taskID))
+	}
+	return fmt.Sprintf("mark %v done, %v → %v", taskName(data, c.taskID), listName(data, listIdx), listName(data, c.doneListIdx))
+}
+
+func (m *MoveTaskToBoardCommand) Describe(data *parser.Data) string {
+	boardName := ""
+	if boardNames := data.GetBoardNames(); m.newBoardIdx < len(boardNames) {
+		boardName = boardNames[m.newBoardIdx]
+	}
+	newListName := ""
+	if listNames, err := data.GetBoardListNames(m.newBoardIdx); err == nil && m.newListIdx < len(listNames) {
+		newListName = listNames[m.newListIdx]
+	}
+	return fmt.Sprintf("move %v to %v › %v", taskName(data, m.taskID), boardName, newListName)
+}
+
+func (e *EditTaskCommand) Describe(data *parser.Data) string {
+	task, err := data.GetTaskByID(e.taskID)
+	if err == nil && task.ItemName != e.task.ItemName {
+		return fmt.Sprintf("rename %v → %v", quote(task.ItemName), quote(e.task.ItemName))
+	}
+	return fmt.Sprintf("edit %v", taskName(data, e.taskID))
+}
+
+func (a *AddSubtaskCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("add %v to the checklist of %v", quote(a.subtask.Name), taskName(data, a.taskID))
+}
+
+func (r *RemoveSubtaskCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("delete %v from the checklist of %v", subtaskName(data, r.taskID, r.subtaskIdx), taskName(data, r.taskID))
+}
+
+func (t *ToggleSubtaskCommand) Describe(data *parser.Data) string {
+	action := "check"
+	if subtasks, err := data.GetSubtasks(t.taskID); err == nil && t.subtaskIdx < len(subtasks) && subtasks[t.subtaskIdx].Done {
+		action = "uncheck"
+	}
+	return fmt.Sprintf("%v %v of %v", action, subtaskName(data, t.taskID, t.subtaskIdx), taskName(data, t.taskID))
+}
+
+func (s *SwapSubtaskCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("reorder the checklist of %v", taskName(data, s.taskID))
+}
+
+func (s *SetTaskDoneCommand) Describe(data *parser.Data) string {
+	if !s.done {
+		return fmt.Sprintf("mark %v not done", taskName(data, s.taskID))
+	}
+	return fmt.Sprintf("mark %v done", taskName(data, s.taskID))
+}
+
+func (a *AddListCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("add list %v", quote(a.listTitle))
+}
+
+func (r *RenameListCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("rename list %v → %v", quote(listName(data, r.listIdx)), quote(r.listTitle))
+}
+
+func (r *RemoveListCommand) Describe(data *parser.Data) string {
+	taskCount, _ := data.GetTaskCount(r.listIdx)
+	if taskCount == 0 {
+		return fmt.Sprintf("delete list %v", quote(listName(data, r.listIdx)))
+	}
+	if r.destListIdx < 0 {
+		return fmt.Sprintf("delete list %v and its %v tasks", quote(listName(data, r.listIdx)), taskCount)
+	}
+	return fmt.Sprintf("delete list %v, its tasks → %v", quote(listName(data, r.listIdx)), listName(data, r.destListIdx))
+}
+
+func (m *MoveListCommand) Describe(data *parser.Data) string {
+	direction := "right"
+	if m.newListIdx < m.listIdx {
+		direction = "left"
+	}
+	return fmt.Sprintf("move list %v %v", quote(listName(data, m.listIdx)), direction)
+}
+
+func (r *RestoreBackupCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("restore the backup of %v", r.backupTime.Format(time.DateTime))
+}
+
+func (a *ArchiveCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("archive %v done tasks", data.CountArchivable(a.doneBefore))
+}
+
+func (u *UnarchiveTaskCommand) Describe(data *parser.Data) string {
+	return fmt.Sprintf("unarchive %v", quote(u.archivedTask.Task.ItemName))
+}
+
+// the descriptions of the steps, known once they ran
+func (c *CompositeCommand) Describe(data *parser.Data) string {
+	var descriptions []string
+	for _, description := range c.descriptions {
+		if len(description) > 0 {
+			descriptions = append(descriptions, description)
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func (e EmptyCommand) Describe(data *parser.Data) string {
+	return ""
+}
+
+func quote(name string) string {
+	return "'" + name + "'"
+}
+
+// returns the quoted name of the task of the active board with the id
+func taskName(data *parser.Data, taskID string) string {
+	task, err := data.GetTaskByID(taskID)
+	if err != nil {
+		return taskID
+	}
+	return quote(task.ItemName)
+}
+
+// returns the title of the list of the active board
+func listName(data *parser.Data, listIdx int) string {
+	listNames := data.GetListNames()
+	if listIdx < 0 || listIdx >= len(listNames) {
+		return ""
+	}
+	return listNames[listIdx]
+}
+
+// returns the quoted name of the checklist item of the task
+func subtaskName(data *parser.Data, taskID string, subtaskIdx int) string {
+	subtasks, err := data.GetSubtasks(taskID)
+	if err != nil || subtaskIdx < 0 || subtaskIdx >= len(subtasks) {
+		return ""
+	}
+	return quote(subtasks[subtaskIdx].Name)
+}
@@ -0,0 +1,811 @@
+package command
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+	"github.com/ppriyankuu/seiban/pkg/parser"
+)
+
+// the history is kept in a journal next to the board file, so that undo and
+// redo work after seiban restarts. the first line of the journal holds the
+// first state of the history, see undotree.go, and the state the boards were
+// in when the journal was written. a line is appended for every state a
+// command adds, with the state it was made in and the command that made it,
+// and after every command executed, undone or redone a line with the current
+// state, a hash of the boards in it and the states redo goes to that changed:
+//
+//	{"position":0,"content":"9f86d0...","time":"2026-10-18T09:12:00Z"}
+//	{"parent":0,"time":"2026-10-18T09:12:05Z","type":"add-task","board":0,"description":"add 'fix login' to TODO","command":{...}}
+//	{"position":1,"content":"3a7bd3...","redo":[[0,1]]}
+//	{"parent":1,"time":"2026-10-18T09:13:40Z","type":"move-task","board":0,"description":"move 'fix login' TODO → DOING","command":{...}}
+//	{"position":2,"content":"b5bb9d...","redo":[[1,2]]}
+//
+// the journal is written again from scratch, with only the states in it,
+// once it holds many more lines than states or old states were dropped. a
+// journal written for other boards (e.g. the file was changed by an editor
+// since) is discarded.
+
+// most commands kept in the journal, the oldest ones are dropped, see prune
+const maxJournalCommands = 1000
+
+// lines the journal may hold beyond two for every state before it is written again
+const journalSlack = 100
+
+// the types of the commands in the journal
+var commandTypes = map[string]func() Command{
+	"add-task":           func() Command { return &AddTaskCommand{} },
+	"remove-task":        func() Command { return &RemoveTaskCommand{} },
+	"swap-tasks":         func() Command { return &SwapListItemCommand{} },
+	"move-task":          func() Command { return &MoveTaskCommand{} },
+	"complete-task":      func() Command { return &CompleteTaskCommand{} },
+	"move-task-to-board": func() Command { return &MoveTaskToBoardCommand{} },
+	"edit-task":          func() Command { return &EditTaskCommand{} },
+	"add-subtask":        func() Command { return &AddSubtaskCommand{} },
+	"remove-subtask":     func() Command { return &RemoveSubtaskCommand{} },
+	"toggle-subtask":     func() Command { return &ToggleSubtaskCommand{} },
+	"swap-subtasks":      func() Command { return &SwapSubtaskCommand{} },
+	"set-task-done":      func() Command { return &SetTaskDoneCommand{} },
+	"composite":          func() Command { return &CompositeCommand{} },
+	"add-list":           func() Command { return &AddListCommand{} },
+	"rename-list":        func() Command { return &RenameListCommand{} },
+	"remove-list":        func() Command { return &RemoveListCommand{} },
+	"move-list":          func() Command { return &MoveListCommand{} },
+	"restore-backup":     func() Command { return &RestoreBackupCommand{} },
+	"archive":            func() Command { return &ArchiveCommand{} },
+	"unarchive-task":     func() Command { return &UnarchiveTaskCommand{} },
+}
+
+type journalHeader struct {
+	Position int       `json:"position"`
+	Content  string    `json:"content"`
+	Time     time.Time `json:"time"`
+	Redo     int       `json:"redo,omitempty"`
+}
+
+type journalEntry struct {
+	Parent      int             `json:"parent"`
+	Redo        int             `json:"redo,omitempty"`
+	Time        time.Time       `json:"time"`
+	Type        string          `json:"type"`
+	Board       int             `json:"board"`
+	Description string          `json:"description,omitempty"`
+	Command     json.RawMessage `json:"command"`
+}
+
+// the state of the history after a command executed, undone or redone
+type journalState struct {
+	Position int    `json:"position"`
+	Content  string `json:"content"`
+	// pairs of a state and the state redo goes to from it
+	Redo [][2]int `json:"redo,omitempty"`
+}
+
+// keeps the history in the journal of the board file: the history of the
+// journal is loaded, if it was written for the boards as they are now, and
+// the journal is written after every command executed, undone or redone
+func (c *CommandManager) UseJournal(fileName string) error {
+	journalFileName, err := files.HistoryFileName(fileName)
+	if err != nil {
+		return err
+	}
+	c.journalFileName = journalFileName
+	history, position, lines, err := readJournal(journalFileName, contentHash(c.data))
+	if err != nil {
+		// written for other boards, or not by this version of seiban
+		os.Remove(journalFileName)
+		return nil
+	}
+	c.history = history
+	c.history_position = position
+	c.journalLines = lines
+	c.markJournaled()
+	return nil
+}
+
+// returns the hash of the boards the journal is written for
+func contentHash(data *parser.Data) string {
+	hash := sha256.Sum256([]byte(strings.Join(data.Content(), "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// returns the history of the journal and its number of lines, an error if it
+// cannot be read or was written for boards with another hash
+func readJournal(journalFileName, hash string) ([]historyEntry, int, int, error) {
+	file, err := os.Open(journalFileName)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<24)
+	if !scanner.Scan() {
+		return nil, 0, 0, fmt.Errorf("Error in journal %v: it is empty", journalFileName)
+	}
+	var header journalHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, 0, 0, err
+	}
+	if header.Time.IsZero() {
+		// a linear history, without the states the commands were executed in
+		return nil, 0, 0, fmt.Errorf("Error in journal %v: it was written by an older version of seiban", journalFileName)
+	}
+	history := []historyEntry{{command: CreateEmptyCommand(), parent: -1, redoChild: header.Redo, time: header.Time}}
+	position, content := header.Position, header.Content
+	lines := 1
+	for scanner.Scan() {
+		lines++
+		// the lines of the states added by commands have a type
+		var line struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, 0, 0, err
+		}
+		if len(line.Type) == 0 {
+			var state journalState
+			if err := json.Unmarshal(scanner.Bytes(), &state); err != nil {
+				return nil, 0, 0, err
+			}
+			for _, redo := range state.Redo {
+				if redo[0] < 0 || redo[0] >= len(history) {
+					return nil, 0, 0, fmt.Errorf("Error in journal %v: state %v out of range", journalFileName, redo[0])
+				}
+				history[redo[0]].redoChild = redo[1]
+			}
+			position, content = state.Position, state.Content
+			continue
+		}
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, 0, 0, err
+		}
+		command, err := unmarshalCommand(entry.Type, entry.Command)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if entry.Parent < 0 || entry.Parent >= len(history) {
+			return nil, 0, 0, fmt.Errorf("Error in journal %v: parent %v out of range", journalFileName, entry.Parent)
+		}
+		history = append(history, historyEntry{
+			command:     command,
+			boardIdx:    entry.Board,
+			description: entry.Description,
+			parent:      entry.Parent,
+			redoChild:   entry.Redo,
+			time:        entry.Time,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+	if content != hash {
+		return nil, 0, 0, fmt.Errorf("Error in journal %v: it was written for other boards", journalFileName)
+	}
+	for idx, entry := range history {
+		if entry.redoChild != 0 && (entry.redoChild >= len(history) || history[entry.redoChild].parent != idx) {
+			return nil, 0, 0, fmt.Errorf("Error in journal %v: state %v redoes a state that is not under it", journalFileName, idx)
+		}
+	}
+	if position < 0 || position >= len(history) {
+		return nil, 0, 0, fmt.Errorf("Error in journal %v: position %v out of range", journalFileName, position)
+	}
+	return history, position, lines, nil
+}
+
+// remembers the states as they are in the journal
+func (c *CommandManager) markJournaled() {
+	c.journalRedo = c.journalRedo[:0]
+	for _, entry := range c.history {
+		c.journalRedo = append(c.journalRedo, entry.redoChild)
+	}
+}
+
+// appends the states added and changed since the journal was last written
+// to it, if there is one. the journal is a convenience, a change that cannot
+// be written to it is still made.
+func (c *CommandManager) writeJournal() error {
+	if len(c.journalFileName) == 0 {
+		return nil
+	}
+	stateCount := len(c.history)
+	c.prune(maxJournalCommands)
+	if len(c.history) < stateCount || c.journalLines == 0 || c.journalLines > 2*len(c.history)+journalSlack {
+		return c.compactJournal()
+	}
+	var lines []string
+	for idx := len(c.journalRedo); idx < len(c.history); idx++ {
+		line, err := c.journalEntryLine(idx)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+	}
+	state := journalState{Position: c.history_position, Content: contentHash(c.data)}
+	for idx, redoChild := range c.journalRedo {
+		if c.history[idx].redoChild != redoChild {
+			state.Redo = append(state.Redo, [2]int{idx, c.history[idx].redoChild})
+		}
+	}
+	line, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	lines = append(lines, string(line))
+	if err := files.AppendFile(lines, c.journalFileName); err != nil {
+		return err
+	}
+	c.journalLines += len(lines)
+	c.markJournaled()
+	return nil
+}
+
+// writes the journal again with only the states of the history
+func (c *CommandManager) compactJournal() error {
+	header, err := json.Marshal(journalHeader{
+		Position: c.history_position,
+		Content:  contentHash(c.data),
+		Time:     c.history[0].time,
+		Redo:     c.history[0].redoChild,
+	})
+	if err != nil {
+		return err
+	}
+	lines := []string{string(header)}
+	for idx := 1; idx < len(c.history); idx++ {
+		line, err := c.journalEntryLine(idx)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.journalFileName), 0755); err != nil {
+		return err
+	}
+	if err := files.WriteFile(lines, c.journalFileName); err != nil {
+		return err
+	}
+	c.journalLines = len(lines)
+	c.markJournaled()
+	return nil
+}
+
+// returns the line of the journal of a state added by a command
+func (c *CommandManager) journalEntryLine(idx int) (string, error) {
+	entry := c.history[idx]
+	commandType, commandJSON, err := marshalCommand(entry.command)
+	if err != nil {
+		return "", err
+	}
+	line, err := json.Marshal(journalEntry{
+		Parent:      entry.parent,
+		Redo:        entry.redoChild,
+		Time:        entry.time,
+		Type:        commandType,
+		Board:       entry.boardIdx,
+		Description: entry.description,
+		Command:     commandJSON,
+	})
+	return string(line), err
+}
+
+// returns the type and the fields of the command in the journal
+func marshalCommand(command Command) (string, json.RawMessage, error) {
+	for commandType, newCommand := range commandTypes {
+		if reflect.TypeOf(newCommand()) == reflect.TypeOf(command) {
+			commandJSON, err := json.Marshal(command)
+			return commandType, commandJSON, err
+		}
+	}
+	return "", nil, fmt.Errorf("Cannot write %T to the journal", command)
+}
+
+// returns the command of the type with the fields of the journal
+func unmarshalCommand(commandType string, commandJSON json.RawMessage) (Command, error) {
+	newCommand, ok := commandTypes[commandType]
+	if !ok {
+		return nil, fmt.Errorf("Error in journal: unknown command %q", commandType)
+	}
+	command := newCommand()
+	if err := json.Unmarshal(commandJSON, command); err != nil {
+		return nil, err
+	}
+	return command, nil
+}
+
+// the fields of the commands, as they are written to the journal. tasks are
+// written as their lines in the board file, so that nothing of them is lost.
+
+type addTaskJSON struct {
+	ListIdx int      `json:"listIdx"`
+	Task    []string `json:"task"`
+	TaskPos int      `json:"taskPos"`
+}
+
+func (a *AddTaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(addTaskJSON{a.listIdx, a.task.Lines(), a.taskPos})
+}
+
+func (a *AddTaskCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields addTaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	a.listIdx, a.taskPos = fields.ListIdx, fields.TaskPos
+	a.task, err = parser.ParseTask(fields.Task)
+	return err
+}
+
+type removeTaskJSON struct {
+	TaskID  string   `json:"taskID"`
+	ListIdx int      `json:"listIdx"`
+	Task    []string `json:"task"`
+	TaskPos int      `json:"taskPos"`
+}
+
+func (r *RemoveTaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(removeTaskJSON{r.taskID, r.listIdx, r.task.Lines(), r.taskPos})
+}
+
+func (r *RemoveTaskCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields removeTaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	r.taskID, r.listIdx, r.taskPos = fields.TaskID, fields.ListIdx, fields.TaskPos
+	r.task, err = parser.ParseTask(fields.Task)
+	return err
+}
+
+type swapListItemJSON struct {
+	TaskIDFirst  string `json:"taskIDFirst"`
+	TaskIDSecond string `json:"taskIDSecond"`
+}
+
+func (s *SwapListItemCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(swapListItemJSON{s.taskIDFirst, s.taskIDSecond})
+}
+
+func (s *SwapListItemCommand) UnmarshalJSON(b []byte) error {
+	var fields swapListItemJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	s.taskIDFirst, s.taskIDSecond = fields.TaskIDFirst, fields.TaskIDSecond
+	return nil
+}
+
+type moveTaskJSON struct {
+	TaskID      string `json:"taskID"`
+	PrevTaskIdx int    `json:"prevTaskIdx"`
+	PrevListIdx int    `json:"prevListIdx"`
+	NewListIdx  int    `json:"newListIdx"`
+}
+
+func (s *MoveTaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moveTaskJSON{s.taskID, s.prevTaskIdx, s.prevListIdx, s.newListIdx})
+}
+
+func (s *MoveTaskCommand) UnmarshalJSON(b []byte) error {
+	var fields moveTaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	s.taskID, s.prevTaskIdx, s.prevListIdx, s.newListIdx = fields.TaskID, fields.PrevTaskIdx, fields.PrevListIdx, fields.NewListIdx
+	return nil
+}
+
+type setTaskDoneJSON struct {
+	TaskID       string `json:"taskID"`
+	Done         bool   `json:"done"`
+	WasDone      bool   `json:"wasDone"`
+	DoneDate     string `json:"doneDate,omitempty"`
+	PrevDoneDate string `json:"prevDoneDate,omitempty"`
+}
+
+func (s *SetTaskDoneCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(setTaskDoneJSON{s.taskID, s.done, s.wasDone, formatDay(s.doneDate), formatDay(s.prevDoneDate)})
+}
+
+func (s *SetTaskDoneCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields setTaskDoneJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	s.taskID, s.done, s.wasDone = fields.TaskID, fields.Done, fields.WasDone
+	if s.doneDate, err = parseDay(fields.DoneDate); err != nil {
+		return err
+	}
+	s.prevDoneDate, err = parseDay(fields.PrevDoneDate)
+	return err
+}
+
+// returns a day as it is written in the board file, empty for the zero time
+func formatDay(day time.Time) string {
+	if day.IsZero() {
+		return ""
+	}
+	return day.Format(parser.DueDateLayout)
+}
+
+// parses a day written by formatDay
+func parseDay(text string) (time.Time, error) {
+	if len(text) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(parser.DueDateLayout, text)
+}
+
+type completeTaskJSON struct {
+	Steps       *CompositeCommand `json:"steps"`
+	TaskID      string            `json:"taskID"`
+	DoneListIdx int               `json:"doneListIdx"`
+}
+
+func (c *CompleteTaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(completeTaskJSON{c.steps, c.taskID, c.doneListIdx})
+}
+
+func (c *CompleteTaskCommand) UnmarshalJSON(b []byte) error {
+	fields := completeTaskJSON{Steps: &CompositeCommand{}}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	c.steps, c.taskID, c.doneListIdx = fields.Steps, fields.TaskID, fields.DoneListIdx
+	return nil
+}
+
+// a step of a composite command
+type compositeStepJSON struct {
+	Type        string          `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Command     json.RawMessage `json:"command"`
+}
+
+func (c *CompositeCommand) MarshalJSON() ([]byte, error) {
+	steps := []compositeStepJSON{}
+	for idx, command := range c.commands {
+		commandType, commandJSON, err := marshalCommand(command)
+		if err != nil {
+			return nil, err
+		}
+		step := compositeStepJSON{Type: commandType, Command: commandJSON}
+		if idx < len(c.descriptions) {
+			step.Description = c.descriptions[idx]
+		}
+		steps = append(steps, step)
+	}
+	return json.Marshal(steps)
+}
+
+func (c *CompositeCommand) UnmarshalJSON(b []byte) error {
+	var steps []compositeStepJSON
+	if err := json.Unmarshal(b, &steps); err != nil {
+		return err
+	}
+	for _, step := range steps {
+		command, err := unmarshalCommand(step.Type, step.Command)
+		if err != nil {
+			return err
+		}
+		c.add(command, step.Description)
+	}
+	return nil
+}
+
+type moveTaskToBoardJSON struct {
+	TaskID       string         `json:"taskID"`
+	PrevBoardIdx int            `json:"prevBoardIdx"`
+	PrevListIdx  int            `json:"prevListIdx"`
+	PrevTaskIdx  int            `json:"prevTaskIdx"`
+	NewBoardIdx  int            `json:"newBoardIdx"`
+	NewListIdx   int            `json:"newListIdx"`
+	Undone       []doneTaskJSON `json:"undone,omitempty"`
+}
+
+func (m *MoveTaskToBoardCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moveTaskToBoardJSON{m.taskID, m.prevBoardIdx, m.prevListIdx, m.prevTaskIdx, m.newBoardIdx, m.newListIdx, marshalDoneTasks(m.undone)})
+}
+
+func (m *MoveTaskToBoardCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields moveTaskToBoardJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	m.taskID, m.prevBoardIdx, m.prevListIdx, m.prevTaskIdx = fields.TaskID, fields.PrevBoardIdx, fields.PrevListIdx, fields.PrevTaskIdx
+	m.newBoardIdx, m.newListIdx = fields.NewBoardIdx, fields.NewListIdx
+	m.undone, err = unmarshalDoneTasks(fields.Undone)
+	return err
+}
+
+type editTaskJSON struct {
+	TaskID       string   `json:"taskID"`
+	Task         []string `json:"task"`
+	OriginalTask []string `json:"originalTask"`
+}
+
+func (e *EditTaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(editTaskJSON{e.taskID, e.task.Lines(), e.originalTask.Lines()})
+}
+
+func (e *EditTaskCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields editTaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	e.taskID = fields.TaskID
+	if e.task, err = parser.ParseTask(fields.Task); err != nil {
+		return err
+	}
+	e.originalTask, err = parser.ParseTask(fields.OriginalTask)
+	return err
+}
+
+type subtaskJSON struct {
+	TaskID     string         `json:"taskID"`
+	Subtask    parser.Subtask `json:"subtask"`
+	SubtaskIdx int            `json:"subtaskIdx"`
+}
+
+func (a *AddSubtaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(subtaskJSON{a.taskID, a.subtask, a.subtaskIdx})
+}
+
+func (a *AddSubtaskCommand) UnmarshalJSON(b []byte) error {
+	var fields subtaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	a.taskID, a.subtask, a.subtaskIdx = fields.TaskID, fields.Subtask, fields.SubtaskIdx
+	return nil
+}
+
+func (r *RemoveSubtaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(subtaskJSON{r.taskID, r.subtask, r.subtaskIdx})
+}
+
+func (r *RemoveSubtaskCommand) UnmarshalJSON(b []byte) error {
+	var fields subtaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	r.taskID, r.subtask, r.subtaskIdx = fields.TaskID, fields.Subtask, fields.SubtaskIdx
+	return nil
+}
+
+func (t *ToggleSubtaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(subtaskJSON{TaskID: t.taskID, SubtaskIdx: t.subtaskIdx})
+}
+
+func (t *ToggleSubtaskCommand) UnmarshalJSON(b []byte) error {
+	var fields subtaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	t.taskID, t.subtaskIdx = fields.TaskID, fields.SubtaskIdx
+	return nil
+}
+
+type swapSubtaskJSON struct {
+	TaskID           string `json:"taskID"`
+	SubtaskIdxFirst  int    `json:"subtaskIdxFirst"`
+	SubtaskIdxSecond int    `json:"subtaskIdxSecond"`
+}
+
+func (s *SwapSubtaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(swapSubtaskJSON{s.taskID, s.subtaskIdxFirst, s.subtaskIdxSecond})
+}
+
+func (s *SwapSubtaskCommand) UnmarshalJSON(b []byte) error {
+	var fields swapSubtaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	s.taskID, s.subtaskIdxFirst, s.subtaskIdxSecond = fields.TaskID, fields.SubtaskIdxFirst, fields.SubtaskIdxSecond
+	return nil
+}
+
+type addListJSON struct {
+	ListIdx   int    `json:"listIdx"`
+	ListTitle string `json:"listTitle"`
+}
+
+func (a *AddListCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(addListJSON{a.listIdx, a.listTitle})
+}
+
+func (a *AddListCommand) UnmarshalJSON(b []byte) error {
+	var fields addListJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	a.listIdx, a.listTitle = fields.ListIdx, fields.ListTitle
+	return nil
+}
+
+type renameListJSON struct {
+	ListIdx         int      `json:"listIdx"`
+	ListTitle       string   `json:"listTitle"`
+	PrevTitle       string   `json:"prevTitle"`
+	PrevFrontMatter []string `json:"prevFrontMatter,omitempty"`
+}
+
+func (r *RenameListCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(renameListJSON{r.listIdx, r.listTitle, r.prevTitle, r.prevFrontMatter})
+}
+
+func (r *RenameListCommand) UnmarshalJSON(b []byte) error {
+	var fields renameListJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	r.listIdx, r.listTitle, r.prevTitle, r.prevFrontMatter = fields.ListIdx, fields.ListTitle, fields.PrevTitle, fields.PrevFrontMatter
+	return nil
+}
+
+type removeListJSON struct {
+	ListIdx         int      `json:"listIdx"`
+	DestListIdx     int      `json:"destListIdx"`
+	List            []string `json:"list"`
+	PrevFrontMatter []string `json:"prevFrontMatter,omitempty"`
+}
+
+func (r *RemoveListCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(removeListJSON{r.listIdx, r.destListIdx, r.list.Lines(), r.prevFrontMatter})
+}
+
+func (r *RemoveListCommand) UnmarshalJSON(b []byte) error {
+	var fields removeListJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	list, err := parser.ParseList(fields.List)
+	if err != nil {
+		return err
+	}
+	r.listIdx, r.destListIdx, r.list, r.prevFrontMatter = fields.ListIdx, fields.DestListIdx, list, fields.PrevFrontMatter
+	return nil
+}
+
+type doneTaskJSON struct {
+	TaskID   string `json:"taskID"`
+	DoneDate string `json:"doneDate,omitempty"`
+}
+
+func marshalDoneTasks(tasks []doneTask) []doneTaskJSON {
+	var fields []doneTaskJSON
+	for _, task := range tasks {
+		fields = append(fields, doneTaskJSON{task.taskID, formatDay(task.doneDate)})
+	}
+	return fields
+}
+
+func unmarshalDoneTasks(fields []doneTaskJSON) ([]doneTask, error) {
+	var tasks []doneTask
+	for _, task := range fields {
+		doneDate, err := parseDay(task.DoneDate)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, doneTask{task.TaskID, doneDate})
+	}
+	return tasks, nil
+}
+
+type moveListJSON struct {
+	ListIdx    int            `json:"listIdx"`
+	NewListIdx int            `json:"newListIdx"`
+	Undone     []doneTaskJSON `json:"undone,omitempty"`
+}
+
+func (m *MoveListCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moveListJSON{m.listIdx, m.newListIdx, marshalDoneTasks(m.undone)})
+}
+
+func (m *MoveListCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields moveListJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	m.listIdx, m.newListIdx = fields.ListIdx, fields.NewListIdx
+	m.undone, err = unmarshalDoneTasks(fields.Undone)
+	return err
+}
+
+type restoreBackupJSON struct {
+	Content     []string  `json:"content"`
+	BackupTime  time.Time `json:"backupTime"`
+	PrevContent []string  `json:"prevContent"`
+}
+
+func (r *RestoreBackupCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(restoreBackupJSON{r.content, r.backupTime, r.prevContent})
+}
+
+func (r *RestoreBackupCommand) UnmarshalJSON(b []byte) error {
+	var fields restoreBackupJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	r.content, r.backupTime, r.prevContent = fields.Content, fields.BackupTime, fields.PrevContent
+	return nil
+}
+
+// an archived task, with the task as its lines in the board file
+type archivedTaskJSON struct {
+	BoardName  string   `json:"boardName"`
+	ArchivedOn string   `json:"archivedOn"`
+	Position   int      `json:"position"`
+	Task       []string `json:"task"`
+}
+
+func marshalArchivedTask(archivedTask parser.ArchivedTask) archivedTaskJSON {
+	return archivedTaskJSON{archivedTask.BoardName, archivedTask.ArchivedOn, archivedTask.Position, archivedTask.Task.Lines()}
+}
+
+func unmarshalArchivedTask(fields archivedTaskJSON) (parser.ArchivedTask, error) {
+	task, err := parser.ParseTask(fields.Task)
+	return parser.ArchivedTask{BoardName: fields.BoardName, ArchivedOn: fields.ArchivedOn, Position: fields.Position, Task: task}, err
+}
+
+type archiveJSON struct {
+	DoneBefore  string             `json:"doneBefore,omitempty"`
+	Archived    []archivedTaskJSON `json:"archived"`
+	PrevContent []string           `json:"prevContent"`
+}
+
+func (a *ArchiveCommand) MarshalJSON() ([]byte, error) {
+	fields := archiveJSON{DoneBefore: formatDay(a.doneBefore), PrevContent: a.prevContent}
+	for _, archivedTask := range a.archived {
+		fields.Archived = append(fields.Archived, marshalArchivedTask(archivedTask))
+	}
+	return json.Marshal(fields)
+}
+
+func (a *ArchiveCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields archiveJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	if a.doneBefore, err = parseDay(fields.DoneBefore); err != nil {
+		return err
+	}
+	a.archived = nil
+	for _, archivedTaskFields := range fields.Archived {
+		archivedTask, err := unmarshalArchivedTask(archivedTaskFields)
+		if err != nil {
+			return err
+		}
+		a.archived = append(a.archived, archivedTask)
+	}
+	a.prevContent = fields.PrevContent
+	return nil
+}
+
+type unarchiveTaskJSON struct {
+	ArchivedTask archivedTaskJSON `json:"archivedTask"`
+	TaskID       string           `json:"taskID"`
+}
+
+func (u *UnarchiveTaskCommand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(unarchiveTaskJSON{marshalArchivedTask(u.archivedTask), u.taskID})
+}
+
+func (u *UnarchiveTaskCommand) UnmarshalJSON(b []byte) (err error) {
+	var fields unarchiveTaskJSON
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	u.taskID = fields.TaskID
+	u.archivedTask, err = unmarshalArchivedTask(fields.ArchivedTask)
+	return err
+}
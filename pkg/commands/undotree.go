@@ -0,0 +1,246 @@
+package command
+
+import (
+	"fmt"
+	"time"
+)
+
+// the history is a tree of the states of the boards. the first state is the
+// boards before any command, every command executed adds a state under the
+// state it was executed in. a command executed after an undo starts a new
+// branch, the undone commands are kept on their own branch. undo goes to the
+// parent state, redo to the child state last visited.
+//
+// the states are numbered in the order their commands were executed. like
+// vim's g- and g+, Older and Newer go to the state before or after the
+// current one in that order, across branches, and Earlier and Later by an
+// amount of time (vim's :earlier 10m and :later 10m).
+
+// a state of the history, as shown to the user
+type HistoryState struct {
+	// number of the state, states are numbered in the order they were made
+	ID int
+	// the state the command was executed in, -1 for the first state
+	Parent int
+	// the command that made the state, empty for the first state
+	Description string
+	// when the state was made
+	Time time.Time
+}
+
+// returns the states of the history, by number
+func (c *CommandManager) States() []HistoryState {
+	states := make([]HistoryState, len(c.history))
+	for idx := range c.history {
+		states[idx] = c.state(idx)
+	}
+	return states
+}
+
+// returns the states undo goes back from, the current one first. the
+// first state, which undo cannot go back from, is left out.
+func (c *CommandManager) Past() []HistoryState {
+	var past []HistoryState
+	for idx := c.history_position; idx > 0; idx = c.history[idx].parent {
+		past = append(past, c.state(idx))
+	}
+	return past
+}
+
+// returns the states redo goes to, the next one first
+func (c *CommandManager) Future() []HistoryState {
+	var future []HistoryState
+	for idx := c.history[c.history_position].redoChild; idx != 0; idx = c.history[idx].redoChild {
+		future = append(future, c.state(idx))
+	}
+	return future
+}
+
+func (c *CommandManager) state(idx int) HistoryState {
+	entry := c.history[idx]
+	return HistoryState{ID: idx, Parent: entry.parent, Description: entry.description, Time: entry.time}
+}
+
+// returns the number of the state the boards are in
+func (c *CommandManager) State() int {
+	return c.history_position
+}
+
+// goes to the state made before the current one
+func (c *CommandManager) Older() error {
+	if c.history_position == 0 {
+		return nil
+	}
+	return c.GoTo(c.history_position - 1)
+}
+
+// goes to the state made after the current one
+func (c *CommandManager) Newer() error {
+	if c.history_position == len(c.history)-1 {
+		return nil
+	}
+	return c.GoTo(c.history_position + 1)
+}
+
+// goes to the last state made at least the duration before the current
+// one, or to the first state if there is none
+func (c *CommandManager) Earlier(duration time.Duration) error {
+	until := c.history[c.history_position].time.Add(-duration)
+	state := 0
+	for idx := c.history_position - 1; idx > 0; idx-- {
+		if !c.history[idx].time.After(until) {
+			state = idx
+			break
+		}
+	}
+	return c.GoTo(state)
+}
+
+// goes to the last state made at most the duration after the current one
+func (c *CommandManager) Later(duration time.Duration) error {
+	until := c.history[c.history_position].time.Add(duration)
+	state := c.history_position
+	for idx := c.history_position + 1; idx < len(c.history); idx++ {
+		if !c.history[idx].time.After(until) {
+			state = idx
+		}
+	}
+	return c.GoTo(state)
+}
+
+// goes to a state of the history, undoing the commands up to the state both
+// states branch from and redoing the commands down to it
+func (c *CommandManager) GoTo(state int) error {
+	if err := c.checkNoTransaction(fmt.Sprintf("go to state %v", state)); err != nil {
+		return err
+	}
+	if state < 0 || state >= len(c.history) {
+		return fmt.Errorf("Cannot go to state %v: there is no such state", state)
+	}
+	if state == c.history_position {
+		return nil
+	}
+	ancestors := map[int]bool{}
+	for idx := c.history_position; idx >= 0; idx = c.history[idx].parent {
+		ancestors[idx] = true
+	}
+	var path []int
+	branchState := state
+	for ; !ancestors[branchState]; branchState = c.history[branchState].parent {
+		path = append(path, branchState)
+	}
+	var err error
+	for err == nil && c.history_position != branchState {
+		err = c.undoState()
+	}
+	for idx := len(path) - 1; err == nil && idx >= 0; idx-- {
+		err = c.redoState(path[idx])
+	}
+	if err != nil {
+		// the boards are in the state the error happened in
+		c.changed(c.stateDescription(c.history_position))
+		return err
+	}
+	c.changed(c.stateDescription(state))
+	return nil
+}
+
+// describes going to a state
+func (c *CommandManager) stateDescription(state int) string {
+	if state == 0 {
+		return "go to the first state"
+	}
+	return fmt.Sprintf("go to state %v (%v)", state, c.history[state].description)
+}
+
+// undoes the command of the current state, going to its parent state
+func (c *CommandManager) undoState() error {
+	entry := c.history[c.history_position]
+	if err := c.data.SetActiveBoard(entry.boardIdx); err != nil {
+		return err
+	}
+	if err := entry.command.Undo(c.data); err != nil {
+		return err
+	}
+	c.history[entry.parent].redoChild = c.history_position
+	c.history_position = entry.parent
+	return nil
+}
+
+// redoes the command of a child state of the current state, going to it
+func (c *CommandManager) redoState(state int) error {
+	entry := c.history[state]
+	if err := c.data.SetActiveBoard(entry.boardIdx); err != nil {
+		return err
+	}
+	if err := entry.command.Do(c.data); err != nil {
+		return err
+	}
+	c.history[entry.parent].redoChild = state
+	c.history_position = state
+	return nil
+}
+
+// drops the oldest states until there are at most the given number of
+// commands: first the branches the current state is not on, then the first
+// state, the command of the second state can no longer be undone then
+func (c *CommandManager) prune(maxCommands int) {
+	for len(c.history)-1 > maxCommands {
+		onPath := make([]bool, len(c.history))
+		for idx := c.history_position; idx >= 0; idx = c.history[idx].parent {
+			onPath[idx] = true
+		}
+		dropped := make([]bool, len(c.history))
+		branch := false
+		for idx := 1; idx < len(c.history) && !branch; idx++ {
+			// the oldest branch off the path to the current state
+			branch = !onPath[idx] && onPath[c.history[idx].parent]
+			dropped[idx] = branch
+		}
+		if !branch {
+			// every state is on the path, the second one becomes the first one
+			second := c.history_position
+			for c.history[second].parent != 0 {
+				second = c.history[second].parent
+			}
+			c.history[second].command = CreateEmptyCommand()
+			c.history[second].description = ""
+			c.history[second].parent = -1
+			dropped[0] = true
+		}
+		c.dropStates(dropped)
+	}
+}
+
+// removes the given states and the states under them from the history,
+// renumbering the states that are left
+func (c *CommandManager) dropStates(dropped []bool) {
+	// states come after their parent state
+	for idx := range c.history {
+		if parent := c.history[idx].parent; parent >= 0 && dropped[parent] {
+			dropped[idx] = true
+		}
+	}
+	newIdxs := make([]int, len(c.history))
+	history := []historyEntry{}
+	for idx, entry := range c.history {
+		if dropped[idx] {
+			continue
+		}
+		newIdxs[idx] = len(history)
+		history = append(history, entry)
+	}
+	for idx := range history {
+		entry := &history[idx]
+		if entry.parent >= 0 {
+			entry.parent = newIdxs[entry.parent]
+		}
+		if dropped[entry.redoChild] {
+			entry.redoChild = 0
+		} else {
+			entry.redoChild = newIdxs[entry.redoChild]
+		}
+	}
+	c.history_position = newIdxs[c.history_position]
+	c.history = history
+}
@@ -0,0 +1,125 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// directory, next to the board file, that holds the backups of the board file
+const BackupDir = ".seiban/backups"
+
+// directory, next to the board file, that holds the undo history and the lock of the board file
+const historyDir = ".seiban"
+
+// number of backups kept of a board file, the oldest ones are removed
+const BackupCount = 10
+
+// layout of the time in the name of a backup
+const backupTimeLayout = "2006-01-02T15-04-05.000"
+
+// a copy of a board file from an earlier time
+type Backup struct {
+	Path string
+	Time time.Time
+}
+
+// returns the directory of the backups and the prefix of their names
+func backupPrefix(fileName string) (string, string, error) {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(filepath.Dir(filePath), BackupDir), filepath.Base(filePath) + ".", nil
+}
+
+// returns the path of the journal of the undo history of the file, e.g.
+// .seiban/seiban.md.history.jsonl for seiban.md
+func HistoryFileName(fileName string) (string, error) {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(filePath), historyDir, filepath.Base(filePath)+".history.jsonl"), nil
+}
+
+// writes the lines as a new backup of the file and removes the backups
+// beyond the newest BackupCount
+func WriteBackup(fileName string, fileContent []string) error {
+	dir, prefix, err := backupPrefix(fileName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	backupPath := filepath.Join(dir, prefix+time.Now().Format(backupTimeLayout)+".md")
+	if err := WriteFile(fileContent, backupPath); err != nil {
+		return err
+	}
+	backups, err := ListBackups(fileName)
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups[min(len(backups), BackupCount):] {
+		os.Remove(backup.Path)
+	}
+	return nil
+}
+
+// returns the backups of the file, the newest first
+func ListBackups(fileName string) ([]Backup, error) {
+	dir, prefix, err := backupPrefix(fileName)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var backups []Backup
+	for _, entry := range entries {
+		stamp, found := strings.CutPrefix(entry.Name(), prefix)
+		if !found || entry.IsDir() {
+			continue
+		}
+		backupTime, err := time.ParseInLocation(backupTimeLayout, strings.TrimSuffix(stamp, ".md"), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Path: filepath.Join(dir, entry.Name()), Time: backupTime})
+	}
+	slices.SortFunc(backups, func(a, b Backup) int {
+		return b.Time.Compare(a.Time)
+	})
+	return backups, nil
+}
+
+// returns how long ago the backup was written, e.g. "3 minutes ago"
+func (b Backup) Age() string {
+	age := time.Since(b.Time)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age/time.Minute), "minute") + " ago"
+	case age < 24*time.Hour:
+		return plural(int(age/time.Hour), "hour") + " ago"
+	default:
+		return plural(int(age/(24*time.Hour)), "day") + " ago"
+	}
+}
+
+// returns the count followed by the word, in plural unless the count is one
+func plural(count int, word string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %v", word)
+	}
+	return fmt.Sprintf("%v %vs", count, word)
+}
@@ -0,0 +1,70 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWriteBackup(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for idx := range BackupCount + 2 {
+		if err := WriteBackup("seiban.md", []string{fmt.Sprint(idx)}); err != nil {
+			t.Fatal(err)
+		}
+		// the names of the backups hold the time to the millisecond
+		time.Sleep(2 * time.Millisecond)
+	}
+	if err := WriteBackup("other.md", []string{"other"}); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := ListBackups("seiban.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != BackupCount {
+		t.Fatalf("%v backups, want %v", len(backups), BackupCount)
+	}
+	// the newest first
+	for idx, backup := range backups {
+		content, err := ReadFile(backup.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{fmt.Sprint(BackupCount + 1 - idx)}; !slices.Equal(content, want) {
+			t.Errorf("backup %v holds %q, want %q", idx, content, want)
+		}
+	}
+	entries, _ := os.ReadDir(BackupDir)
+	if len(entries) != BackupCount+1 {
+		t.Errorf("%v files in %v, want the backups of both files", len(entries), BackupDir)
+	}
+}
+
+func TestListBackupsWithoutBackups(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if backups, err := ListBackups(filepath.Join("sub", "seiban.md")); err != nil || len(backups) != 0 {
+		t.Errorf("ListBackups() = %v, %v, want none", backups, err)
+	}
+}
+
+func TestBackupAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{time.Minute + time.Second, "1 minute ago"},
+		{5 * time.Minute, "5 minutes ago"},
+		{3 * time.Hour, "3 hours ago"},
+		{50 * time.Hour, "2 days ago"},
+	}
+	for _, test := range tests {
+		if got := (Backup{Time: time.Now().Add(-test.age)}).Age(); got != test.want {
+			t.Errorf("Age() of a backup %v old = %q, want %q", test.age, got, test.want)
+		}
+	}
+}
@@ -2,36 +2,66 @@ package files
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"path/filepath"
 )
 
-const initialFileContent = `# %s
+const initialFileContent = `---
+done: %[4]s
+---
 
-## %s
+# %[1]s
 
+## %[2]s
 
-## %s
 
+## %[3]s
 
-## %s
+
+## %[4]s
 
 
 `
 
-// checks if the file is present in the current dir.
+// file names are paths, absolute or relative to the working directory
+
+// checks if the file is present
 func CheckFile(fileName string) bool {
-	dir, err := os.Getwd()
+	filePath, err := FilePath(fileName)
 	if err != nil {
 		log.Fatal(err)
 	}
-	filePath := dir + fileName
 	_, err = os.Stat(filePath)
 	return err == nil
 }
 
+// looks for the file in the working directory and its parents, the way git
+// looks for .git. returns the path of the file relative to the working
+// directory, false if none of the directories has it.
+func FindFile(fileName string) (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	for searchDir := dir; ; searchDir = filepath.Dir(searchDir) {
+		filePath := filepath.Join(searchDir, fileName)
+		if info, err := os.Stat(filePath); err == nil && !info.IsDir() {
+			if relPath, err := filepath.Rel(dir, filePath); err == nil {
+				return relPath, true
+			}
+			return filePath, true
+		}
+		if filepath.Dir(searchDir) == searchDir {
+			return "", false
+		}
+	}
+}
+
 // create a file
 func CreateFile(fileName string) {
 	f, err := OpenFileWriteOnly(fileName)
@@ -49,19 +79,38 @@ func WriteInitialContent(fileName string) {
 	}
 	defer f.Close()
 
-	_, err = f.WriteString(fmt.Sprintf(initialFileContent, GetDirectoryName(), "TODO", "DOING", "DONE"))
+	_, err = f.WriteString(InitialContent(fileName))
 	if err != nil {
 		log.Fatalf("Cannot write to the file (%v): %v", fileName, err)
 	}
 }
 
+// returns the markdown of a new board file, the board is named after the directory of the file
+func InitialContent(fileName string) string {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		log.Fatalf("Cannot create file %q, Err: %v", fileName, err)
+	}
+	boardName := filepath.Base(filepath.Dir(filePath))
+	return fmt.Sprintf(initialFileContent, boardName, "TODO", "DOING", "DONE")
+}
+
 // opens a file in write only mode
 func OpenFileWriteOnly(fileName string) (*os.File, error) {
-	dir, err := os.Getwd()
+	filePath, err := FilePath(fileName)
 	if err != nil {
 		return nil, fmt.Errorf("Cannot open file: %v", err)
 	}
-	return os.OpenFile(dir+fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	return os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+// removes a file
+func RemoveFile(fileName string) error {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filePath)
 }
 
 // returns the name of the current working directory
@@ -70,31 +119,33 @@ func GetDirectoryName() string {
 	if err != nil {
 		log.Fatalf("Cannot get directory name: %v", err)
 	}
-	dirs := strings.Split(dir, "/")
-	dirName := dirs[len(dirs)-1]
-	return dirName
+	return filepath.Base(dir)
 }
 
 // returns the complete path of the file, given the filename
 func FilePath(fileName string) (string, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-	filePath := dir + fileName
-	return filePath, nil
+	return filepath.Abs(fileName)
 }
 
 // opens the given file and returns the content of the file
 // line by line as a slice of string
 func OpenFile(fileName string) []string {
-	filePath, err := FilePath(fileName)
+	fileContent, err := ReadFile(fileName)
 	if err != nil {
 		log.Fatal(err)
 	}
+	return fileContent
+}
+
+// returns the content of the file line by line
+func ReadFile(fileName string) ([]string, error) {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return nil, err
+	}
 	file, err := os.Open(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer file.Close()
 	scanner := bufio.NewScanner(file)
@@ -103,23 +154,133 @@ func OpenFile(fileName string) []string {
 	for scanner.Scan() {
 		fileContent = append(fileContent, scanner.Text())
 	}
-	return fileContent
+	return fileContent, scanner.Err()
+}
+
+// returns the content of the file line by line, and a hash of its bytes as
+// they were read, see Hash
+func ReadFileWithHash(fileName string) ([]string, string, error) {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return nil, "", err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Split(bufio.ScanLines)
+	var fileContent []string
+	for scanner.Scan() {
+		fileContent = append(fileContent, scanner.Text())
+	}
+	return fileContent, Hash(content), scanner.Err()
 }
 
-// writes a slice of string to a file line by line
-func WriteFile(fileContent []string, fileName string) error {
+// returns a hash of the bytes of the file
+func HashFile(fileName string) (string, error) {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return Hash(content), nil
+}
+
+// returns the hash of the file WriteFile writes for the lines
+func HashLines(fileContent []string) string {
+	var content bytes.Buffer
+	for _, line := range fileContent {
+		content.WriteString(line)
+		content.WriteByte('\n')
+	}
+	return Hash(content.Bytes())
+}
+
+// returns a hash of the bytes, to tell whether a file changed
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// writes a slice of string to a file line by line. the lines are written to
+// a temporary file in the same directory which then replaces the file, so a
+// crash or a full disk never leaves the file half written. the permissions
+// of the file are kept.
+func WriteFile(fileContent []string, fileName string) (err error) {
 	filePath, err := FilePath(fileName)
 	if err != nil {
 		return err
 	}
-	file, err := os.Create(filePath)
+	// replacing the file a symlink points to, not the symlink
+	if target, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = target
+	}
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(filePath); err == nil {
+		perm = info.Mode().Perm()
+	}
+	dir := filepath.Dir(filePath)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
 	w := bufio.NewWriter(file)
 	for _, line := range fileContent {
 		fmt.Fprintln(w, line)
 	}
-	return w.Flush()
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if err = file.Chmod(perm); err != nil {
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(file.Name(), filePath); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// appends a slice of string to a file line by line, the file is created if
+// it does not exist. unlike WriteFile the file is not replaced, so a crash
+// can leave the last line half written.
+func AppendFile(lines []string, fileName string) error {
+	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// flushes the entries of a directory to disk, so that a rename in it survives a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
 }
@@ -0,0 +1,66 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile(filepath.Join(dir, "seiban.md"), []byte("old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("seiban.md", filepath.Join(dir, "link.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile([]string{"# b", "", "## TODO"}, "link.md"); err != nil {
+		t.Fatal(err)
+	}
+	if got := OpenFile("seiban.md"); !slices.Equal(got, []string{"# b", "", "## TODO"}) {
+		t.Errorf("file holds %q", got)
+	}
+	// the symlink still points to the file, which keeps its permissions
+	if info, err := os.Lstat(filepath.Join(dir, "link.md")); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("link.md is not a symlink anymore: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "seiban.md")); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("permissions of the file = %v (%v), want 0600", info.Mode().Perm(), err)
+	}
+	// no temporary file is left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("the directory holds %v files, want the file and the link", len(entries))
+	}
+}
+
+func TestWriteFileError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := WriteFile([]string{"# b"}, "missing/seiban.md"); err == nil {
+		t.Error("writing to a directory that does not exist succeeded")
+	}
+}
+
+func TestFindFile(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "seiban.md"), []byte("# b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// a directory of the same name is not the file
+	if err := os.Mkdir(filepath.Join(dir, "a", "seiban.md"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	if fileName, found := FindFile("seiban.md"); !found || fileName != filepath.Join("..", "..", "seiban.md") {
+		t.Errorf("FindFile() = %q, %v, want the file two directories up", fileName, found)
+	}
+	if fileName, found := FindFile("missing.md"); found {
+		t.Errorf("FindFile() of a missing file = %q", fileName)
+	}
+}
@@ -0,0 +1,37 @@
+package files
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// commits the files to the git repository they are in, alone: changes staged
+// for other files are left out of the commit. only the local repository is
+// used, nothing is fetched or pushed. reports false if the files have nothing
+// to commit.
+func GitCommitFiles(message string, fileNames ...string) (bool, error) {
+	var filePaths []string
+	for _, fileName := range fileNames {
+		filePath, err := FilePath(fileName)
+		if err != nil {
+			return false, err
+		}
+		filePaths = append(filePaths, filePath)
+	}
+	git := func(args ...string) *exec.Cmd {
+		args = append(append([]string{"-C", filepath.Dir(filePaths[0])}, args...), "--")
+		return exec.Command("git", append(args, filePaths...)...)
+	}
+	if output, err := git("add").CombinedOutput(); err != nil {
+		return false, fmt.Errorf("Error in git add: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	if err := git("diff", "--cached", "--quiet").Run(); err == nil {
+		return false, nil
+	}
+	if output, err := git("commit", "--quiet", "-m", message).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("Error in git commit: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return true, nil
+}
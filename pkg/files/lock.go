@@ -0,0 +1,98 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// an advisory lock on a board file, held by one seiban at a time.
+// the lock is taken on a "<name>.lock" file in the .seiban directory next to
+// the board file, as saving replaces the board file itself. the lock file
+// holds the pid of the process that holds the lock. it is not removed on
+// Unlock, a process waiting for the lock holds it open.
+type Lock struct {
+	file *os.File
+}
+
+// returned by LockFile when another process holds the lock
+type LockedError struct {
+	FileName string
+	// pid of the process that holds the lock, 0 if unknown
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("%v is open in another seiban", e.FileName)
+	}
+	return fmt.Sprintf("%v is open in another seiban (pid %v)", e.FileName, e.PID)
+}
+
+// returns the path of the lock file of a board file
+func lockFilePath(fileName string) (string, error) {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(filePath), historyDir, filepath.Base(filePath)+".lock"), nil
+}
+
+// takes the lock of the file, returns a *LockedError if another process holds it
+func LockFile(fileName string) (*Lock, error) {
+	return lockFile(fileName, false)
+}
+
+// takes the lock of the file, waiting until it is released if another process holds it
+func WaitLockFile(fileName string) (*Lock, error) {
+	return lockFile(fileName, true)
+}
+
+func lockFile(fileName string, wait bool) (*Lock, error) {
+	lockPath, err := lockFilePath(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("Cannot open lock file: %v", err)
+	}
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open lock file: %v", err)
+	}
+	locked, err := flock(file, wait)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Cannot lock file %v: %v", fileName, err)
+	}
+	if !locked {
+		pid := readLockPID(file)
+		file.Close()
+		return nil, &LockedError{FileName: fileName, PID: pid}
+	}
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{file: file}, nil
+}
+
+func readLockPID(file *os.File) int {
+	content := make([]byte, 32)
+	n, _ := file.ReadAt(content, 0)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content[:n])))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+// releases the lock
+func (l *Lock) Unlock() error {
+	if err := funlock(l.file); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
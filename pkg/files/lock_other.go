@@ -0,0 +1,14 @@
+//go:build !unix
+
+package files
+
+import "os"
+
+// flock is not available, every process gets the lock
+func flock(file *os.File, wait bool) (bool, error) {
+	return true, nil
+}
+
+func funlock(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build unix
+
+package files
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	lock, err := LockFile("seiban.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LockFile("seiban.md")
+	var lockedError *LockedError
+	if !errors.As(err, &lockedError) {
+		t.Fatalf("LockFile() of a locked file = %v, want a LockedError", err)
+	}
+	if lockedError.PID != os.Getpid() || lockedError.FileName != "seiban.md" {
+		t.Errorf("LockedError = %+v, want the pid of this process", lockedError)
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	lock, err = LockFile("seiban.md")
+	if err != nil {
+		t.Fatalf("LockFile() after Unlock: %v", err)
+	}
+	lock.Unlock()
+	// nothing is left next to the board file
+	if entries, _ := os.ReadDir("."); len(entries) != 1 || entries[0].Name() != ".seiban" {
+		t.Errorf("files next to the board file: %v", entries)
+	}
+}
+
+func TestWaitLockFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	lock, err := LockFile("seiban.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked := make(chan *Lock)
+	go func() {
+		waitingLock, err := WaitLockFile("seiban.md")
+		if err != nil {
+			t.Error(err)
+		}
+		locked <- waitingLock
+	}()
+	select {
+	case <-locked:
+		t.Fatal("WaitLockFile() returned while the file was locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	lock.Unlock()
+	select {
+	case waitingLock := <-locked:
+		waitingLock.Unlock()
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitLockFile() did not return once the file was unlocked")
+	}
+}
@@ -0,0 +1,64 @@
+package files
+
+import (
+	"os"
+	"time"
+)
+
+// how often a file is checked when it cannot be watched with inotify
+const pollInterval = time.Second
+
+// watches a file for changes made by other programs (an editor, git, ...)
+type Watcher struct {
+	done chan struct{}
+	// releases what the watcher uses, after done is closed
+	close func()
+}
+
+// calls onChange, from another goroutine, every time the file changes on disk.
+// saves of the file itself are reported too.
+func WatchFile(fileName string, onChange func()) (*Watcher, error) {
+	filePath, err := FilePath(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if w, err := watchFile(filePath, onChange); err == nil {
+		return w, nil
+	}
+	return pollFile(filePath, onChange), nil
+}
+
+// stops watching the file
+func (w *Watcher) Close() {
+	close(w.done)
+	if w.close != nil {
+		w.close()
+	}
+}
+
+// checks the modification time and size of the file every pollInterval
+func pollFile(filePath string, onChange func()) *Watcher {
+	w := &Watcher{done: make(chan struct{})}
+	go func() {
+		lastInfo, _ := os.Stat(filePath)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(filePath)
+			if err != nil {
+				// removed, e.g. in the middle of a save that replaces it
+				continue
+			}
+			if lastInfo == nil || !info.ModTime().Equal(lastInfo.ModTime()) || info.Size() != lastInfo.Size() {
+				lastInfo = info
+				onChange()
+			}
+		}
+	}()
+	return w
+}
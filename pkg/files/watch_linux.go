@@ -0,0 +1,85 @@
+//go:build linux
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// events of a directory entry that mean the file was written or replaced
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_CREATE
+
+// changes that come in quick succession (a save is a create, a write and a
+// rename) are reported once, this long after the first one
+const settleTime = 50 * time.Millisecond
+
+// watches the directory of the file with inotify, as saves (by seiban,
+// editors and git) usually replace the file instead of writing to it
+func watchFile(filePath string, onChange func()) (*Watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := syscall.InotifyAddWatch(fd, filepath.Dir(filePath), inotifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	// a non-blocking fd is read through the runtime poller, so Close stops a pending Read
+	file := os.NewFile(uintptr(fd), "inotify")
+	w := &Watcher{done: make(chan struct{}), close: func() { file.Close() }}
+	changes := make(chan struct{}, 1)
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				close(changes)
+				return
+			}
+			if changedIn(buf[:n], filepath.Base(filePath)) {
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	go func() {
+		for range changes {
+			time.Sleep(settleTime)
+			select {
+			case <-changes:
+			default:
+			}
+			select {
+			case <-w.done:
+				return
+			default:
+				onChange()
+			}
+		}
+	}()
+	return w, nil
+}
+
+// reports whether the inotify events read into buf concern the file named name
+func changedIn(buf []byte, name string) bool {
+	for offset := 0; offset+syscall.SizeofInotifyEvent <= len(buf); {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		nameStart := offset + syscall.SizeofInotifyEvent
+		nameEnd := nameStart + int(event.Len)
+		if nameEnd > len(buf) {
+			return false
+		}
+		if strings.TrimRight(string(buf[nameStart:nameEnd]), "\x00") == name {
+			return true
+		}
+		offset = nameEnd
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package files
+
+import "errors"
+
+// inotify is only available on linux, the file is polled instead
+func watchFile(filePath string, onChange func()) (*Watcher, error) {
+	return nil, errors.New("inotify is not available")
+}
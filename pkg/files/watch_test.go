@@ -0,0 +1,32 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	filePath := filepath.Join(dir, "seiban.md")
+	if err := os.WriteFile(filePath, []byte("# b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan bool, 10)
+	watcher, err := WatchFile("seiban.md", func() { changed <- true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	// replaced, the way seiban and most editors save
+	if err := WriteFile([]string{"# b", "## TODO"}, "seiban.md"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change was not reported")
+	}
+}
@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+// the archive of a board file is a board file of its own, seiban.md has
+// seiban.archive.md. it has a board for every board tasks were archived
+// from, with a list for every day tasks were archived on, the newest first:
+//
+//	# seiban
+//
+//	## 2026-10-18
+//		- [x] fix login @done(2026-10-02) <!-- id:a1b2c3d4 -->
+
+// a task of the archive file
+type ArchivedTask struct {
+	// name of the board the task was archived from
+	BoardName string
+	// day the task was archived, the title of its list in the archive
+	ArchivedOn string
+	// index of the task in its list of the archive
+	Position int
+	Task     ListItem
+}
+
+// returns the name of the archive file of a board file
+func ArchiveFileName(fileName string) string {
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".archive.md"
+}
+
+// reads the archive of the boards, it is empty if there is no archive file yet
+func (d *Data) OpenArchive() (*Data, error) {
+	if len(d.fileName) == 0 {
+		return nil, fmt.Errorf("Cannot open the archive: the boards are not kept in a file")
+	}
+	archive := &Data{readOnly: d.readOnly, boards: []Board{}}
+	archive.SetFileName(ArchiveFileName(d.fileName))
+	if !files.CheckFile(archive.fileName) {
+		return archive, nil
+	}
+	if err := archive.Load(); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// returns the day before which done tasks are archived when they are kept in
+// the done list for the given number of days, zero (all of them) for 0 days
+func ArchiveBefore(days int) time.Time {
+	if days <= 0 {
+		return time.Time{}
+	}
+	return today().AddDate(0, 0, -days)
+}
+
+// reports whether a task of a done list is archived by Archive
+func archivable(task ListItem, doneBefore time.Time) bool {
+	return doneBefore.IsZero() || (!task.DoneDate.IsZero() && task.DoneDate.Before(doneBefore))
+}
+
+// returns the number of tasks Archive would archive
+func (d *Data) CountArchivable(doneBefore time.Time) int {
+	count := 0
+	for _, board := range d.boards {
+		if doneListIdx := d.doneListIdx(&board); doneListIdx >= 0 {
+			for _, task := range board.lists[doneListIdx].listItems {
+				if archivable(task, doneBefore) {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// moves the tasks of the done lists of all the boards that were done before
+// the given day, or all of them if it is zero, to the archive file. tasks
+// without the day they were done count as done today, they are only archived
+// with all the others. returns the tasks as they are in the archive.
+func (d *Data) Archive(doneBefore time.Time) ([]ArchivedTask, error) {
+	archive, err := d.OpenArchive()
+	if err != nil {
+		return nil, err
+	}
+	archivedOn := today().Format(DueDateLayout)
+	var archived []ArchivedTask
+	for boardIdx := range d.boards {
+		board := &d.boards[boardIdx]
+		doneListIdx := d.doneListIdx(board)
+		if doneListIdx < 0 {
+			continue
+		}
+		doneList := &board.lists[doneListIdx]
+		var keptTasks []ListItem
+		for _, task := range doneList.listItems {
+			if !archivable(task, doneBefore) {
+				keptTasks = append(keptTasks, task)
+				continue
+			}
+			archiveList := archive.archiveList(board.boardName, archivedOn)
+			archived = append(archived, ArchivedTask{BoardName: board.boardName, ArchivedOn: archivedOn, Position: len(archiveList.listItems), Task: task})
+			archiveList.listItems = append(archiveList.listItems, task)
+		}
+		doneList.listItems = keptTasks
+	}
+	if len(archived) == 0 {
+		return nil, nil
+	}
+	// the archive is written first, so that a failed save never loses tasks
+	if err := archive.Save(); err != nil {
+		return nil, err
+	}
+	return archived, d.Save()
+}
+
+// undoes Archive: the boards are replaced by content, their lines before
+// the tasks were archived, and saved, then the archived tasks are removed
+// from the archive file
+func (d *Data) RestoreArchived(content []string, archived []ArchivedTask) error {
+	if err := d.ReplaceContent(content); err != nil {
+		return err
+	}
+	// the boards are written first, so that a failed save never loses tasks
+	if err := d.Save(); err != nil {
+		return err
+	}
+	archive, err := d.OpenArchive()
+	if err != nil {
+		return err
+	}
+	for idx := len(archived) - 1; idx >= 0; idx-- {
+		if boardIdx, listIdx, taskIdx, ok := archive.findArchived(archived[idx]); ok {
+			archive.removeArchived(boardIdx, listIdx, taskIdx)
+		}
+	}
+	return archive.saveArchive()
+}
+
+// returns the list of the archive for the tasks of a board archived on a
+// day, the board and the list are added if they are not there yet
+func (d *Data) archiveList(boardName, archivedOn string) *List {
+	boardIdx := -1
+	for idx, board := range d.boards {
+		if board.boardName == boardName {
+			boardIdx = idx
+		}
+	}
+	if boardIdx < 0 && len(d.boards) == 1 && len(d.boards[0].boardName) == 0 && len(d.boards[0].lists) == 0 {
+		// the board of an empty file
+		d.boards[0].boardName = boardName
+		boardIdx = 0
+	}
+	if boardIdx < 0 {
+		d.boards = append(d.boards, Board{boardName: boardName})
+		boardIdx = len(d.boards) - 1
+	}
+	board := &d.boards[boardIdx]
+	for listIdx := range board.lists {
+		if board.lists[listIdx].listTitle == archivedOn {
+			return &board.lists[listIdx]
+		}
+	}
+	// the newest day first
+	listIdx := 0
+	for listIdx < len(board.lists) && board.lists[listIdx].listTitle > archivedOn {
+		listIdx++
+	}
+	board.lists = slices.Insert(board.lists, listIdx, List{listTitle: archivedOn})
+	return &board.lists[listIdx]
+}
+
+// returns where the archived task is in the archive
+func (d *Data) findArchived(archivedTask ArchivedTask) (boardIdx, listIdx, taskIdx int, ok bool) {
+	for boardIdx, board := range d.boards {
+		if board.boardName != archivedTask.BoardName {
+			continue
+		}
+		for listIdx, list := range board.lists {
+			if list.listTitle != archivedTask.ArchivedOn {
+				continue
+			}
+			for taskIdx, task := range list.listItems {
+				if task.ID == archivedTask.Task.ID {
+					return boardIdx, listIdx, taskIdx, true
+				}
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// removes a task from the archive, and its list and board once they are empty
+func (d *Data) removeArchived(boardIdx, listIdx, taskIdx int) {
+	archiveBoard := &d.boards[boardIdx]
+	archiveList := &archiveBoard.lists[listIdx]
+	archiveList.listItems = slices.Delete(archiveList.listItems, taskIdx, taskIdx+1)
+	if len(archiveList.listItems) == 0 {
+		archiveBoard.lists = slices.Delete(archiveBoard.lists, listIdx, listIdx+1)
+	}
+	if len(archiveBoard.lists) == 0 {
+		d.boards = slices.Delete(d.boards, boardIdx, boardIdx+1)
+		d.boardIdx = 0
+	}
+}
+
+// writes the archive file, which is removed once the last task is back on the boards
+func (d *Data) saveArchive() error {
+	if len(d.boards) == 0 {
+		if !files.CheckFile(d.fileName) {
+			return nil
+		}
+		return files.RemoveFile(d.fileName)
+	}
+	return d.Save()
+}
+
+// returns the tasks of the archive, by board and the newest first
+func (d *Data) ArchivedTasks() []ArchivedTask {
+	var archivedTasks []ArchivedTask
+	for _, board := range d.boards {
+		for _, list := range board.lists {
+			for taskIdx, task := range list.listItems {
+				archivedTasks = append(archivedTasks, ArchivedTask{BoardName: board.boardName, ArchivedOn: list.listTitle, Position: taskIdx, Task: task})
+			}
+		}
+	}
+	return archivedTasks
+}
+
+// moves an archived task back to the end of the done list of the board it
+// was archived from, or of the active board if there is no board of that
+// name anymore. the task gets a new id if a task of the boards has its id,
+// the id it has on the boards is returned.
+func (d *Data) Unarchive(archivedTask ArchivedTask) (string, error) {
+	archive, err := d.OpenArchive()
+	if err != nil {
+		return "", err
+	}
+	boardIdx, listIdx, taskIdx, ok := archive.findArchived(archivedTask)
+	if !ok {
+		return "", fmt.Errorf("Cannot unarchive %q: it is not in the archive anymore", archivedTask.Task.ItemName)
+	}
+	task := archive.boards[boardIdx].lists[listIdx].listItems[taskIdx]
+	destBoard := d.board()
+	for idx := range d.boards {
+		if d.boards[idx].boardName == archivedTask.BoardName {
+			destBoard = &d.boards[idx]
+		}
+	}
+	doneListIdx := d.doneListIdx(destBoard)
+	if doneListIdx < 0 {
+		return "", fmt.Errorf("Cannot unarchive %q: the board %q has no lists", task.ItemName, destBoard.boardName)
+	}
+	if _, _, _, err := d.findTaskInFile(task.ID); err == nil {
+		task.ID = d.NewTaskID()
+	}
+	destBoard.lists[doneListIdx].listItems = append(destBoard.lists[doneListIdx].listItems, task)
+	// the boards are written first, so that a failed save never loses the task
+	if err := d.Save(); err != nil {
+		return "", err
+	}
+	archive.removeArchived(boardIdx, listIdx, taskIdx)
+	return task.ID, archive.saveArchive()
+}
+
+// undoes Unarchive: the task with the id is removed from the boards and the
+// archived task is put back where it was in the archive
+func (d *Data) Rearchive(taskID string, archivedTask ArchivedTask) error {
+	boardIdx, listIdx, taskIdx, err := d.findTaskInFile(taskID)
+	if err != nil {
+		return err
+	}
+	archive, err := d.OpenArchive()
+	if err != nil {
+		return err
+	}
+	archiveList := archive.archiveList(archivedTask.BoardName, archivedTask.ArchivedOn)
+	position := max(0, min(archivedTask.Position, len(archiveList.listItems)))
+	archiveList.listItems = slices.Insert(archiveList.listItems, position, archivedTask.Task)
+	// the archive is written first, so that a failed save never loses the task
+	if err := archive.Save(); err != nil {
+		return err
+	}
+	list := &d.boards[boardIdx].lists[listIdx]
+	list.listItems = slices.Delete(list.listItems, taskIdx, taskIdx+1)
+	return d.Save()
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+func TestArchive(t *testing.T) {
+	done := time.Now().Format(DueDateLayout)
+	d, _ := saveText(t, "# b\n## TODO\n- one <!-- id:a1 -->\n## DONE\n- [x] old @done(2020-01-02) <!-- id:b2 -->\n- [x] new @done("+done+") <!-- id:c3 -->\n")
+	if archived, err := d.Archive(ArchiveBefore(7)); err != nil || len(archived) != 1 || archived[0].Task.ID != "b2" {
+		t.Fatalf("Archive() = %+v, %v, want the old task archived", archived, err)
+	}
+	if count, _ := d.GetTaskCount(1); count != 1 {
+		t.Errorf("%v tasks left in the done list, want 1", count)
+	}
+	archive, err := d.OpenArchive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archivedTasks := archive.ArchivedTasks()
+	if len(archivedTasks) != 1 || archivedTasks[0].BoardName != "b" || archivedTasks[0].ArchivedOn != done || archivedTasks[0].Task.ID != "b2" {
+		t.Fatalf("archived tasks = %+v", archivedTasks)
+	}
+	saved := strings.Join(files.OpenFile("seiban.archive.md"), "\n")
+	if !strings.Contains(saved, "# b") || !strings.Contains(saved, "## "+done) || !strings.Contains(saved, "- [x] old @done(2020-01-02) <!-- id:b2 -->") {
+		t.Errorf("archive saved as\n%v", saved)
+	}
+
+	if taskID, err := d.Unarchive(archivedTasks[0]); err != nil || taskID != "b2" {
+		t.Fatalf("Unarchive() = %v, %v", taskID, err)
+	}
+	if task, err := d.GetTask(1, 1); err != nil || task.ID != "b2" || !task.Done {
+		t.Errorf("unarchived task = %+v, %v, want it at the end of the done list", task, err)
+	}
+	if reopened, _ := d.OpenArchive(); len(reopened.ArchivedTasks()) != 0 {
+		t.Errorf("archived tasks after unarchiving = %+v", reopened.ArchivedTasks())
+	}
+}
+
+func TestArchiveKeepsTasksWithoutDoneDay(t *testing.T) {
+	d, _ := saveText(t, "# b\n## TODO\n## DONE\n- [x] old @done(2020-01-02) <!-- id:a1 -->\n- [x] undated <!-- id:b2 -->\n")
+	if archived, err := d.Archive(ArchiveBefore(14)); err != nil || len(archived) != 1 {
+		t.Fatalf("Archive() = %+v, %v, want the old task archived", archived, err)
+	}
+	if task, err := d.GetTask(1, 0); err != nil || task.ID != "b2" {
+		t.Errorf("done list = %+v, %v, want the undated task", task, err)
+	}
+	// archiving all of them archives it too
+	if archived, err := d.Archive(ArchiveBefore(0)); err != nil || len(archived) != 1 {
+		t.Errorf("Archive() = %+v, %v, want the undated task archived", archived, err)
+	}
+}
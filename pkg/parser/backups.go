@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+// least time between two backups of a file. the first save after it backs
+// up the boards as they were before the save.
+const backupInterval = 5 * time.Minute
+
+// backs up the boards as they were before a save, unless the last backup
+// of the file is less than backupInterval old
+func (d *Data) backup(savedContent []string) error {
+	if len(d.fileName) == 0 || len(savedContent) == 0 {
+		return nil
+	}
+	backups, err := files.ListBackups(d.fileName)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 && time.Since(backups[0].Time) < backupInterval {
+		return nil
+	}
+	return files.WriteBackup(d.fileName, savedContent)
+}
+
+// returns the backups of the file, the newest first
+func (d *Data) GetBackups() ([]files.Backup, error) {
+	if len(d.fileName) == 0 {
+		return nil, nil
+	}
+	return files.ListBackups(d.fileName)
+}
+
+// replaces the board file with the backup without reading the boards of
+// the board file, so that a board file with mistakes can be restored too.
+// the board file is backed up first, so restoring can be undone by
+// restoring that backup. backups hold the boards in markdown, a markdown
+// board file gets the lines of the backup as they are, a JSON board file
+// the boards of the backup.
+func RestoreBackupFile(fileName string, backup files.Backup) error {
+	backupContent, err := files.ReadFile(backup.Path)
+	if err != nil {
+		return err
+	}
+	storage := storageFor(fileName)
+	_, markdown := storage.(*MarkdownStorage)
+	if files.CheckFile(fileName) {
+		fileContent, err := files.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		if !markdown {
+			// backed up in markdown if its boards can be read, else as it is
+			current := &Data{fileName: fileName, storage: storage}
+			if err := current.Load(); err == nil {
+				fileContent = current.Content()
+			}
+		}
+		if err := files.WriteBackup(fileName, fileContent); err != nil {
+			return err
+		}
+	}
+	if markdown {
+		return files.WriteFile(backupContent, fileName)
+	}
+	restored := &Data{fileName: fileName, storage: storage}
+	if err := restored.ParseData(backupContent); err != nil {
+		return err
+	}
+	_, err = storage.Save(restored)
+	return err
+}
+
+// replaces the boards by the ones of the lines of a board file (e.g. of a
+// backup) without saving them, the board with the name of the active board
+// stays active
+func (d *Data) ReplaceContent(fileContent []string) error {
+	replaced := &Data{fileName: d.fileName, readOnly: d.readOnly, storage: d.storage}
+	if err := replaced.ParseData(fileContent); err != nil {
+		return err
+	}
+	replaced.selectBoard(d.GetBoardName())
+	replaced.savedContent = d.savedContent
+	replaced.storedHash = d.storedHash
+	*d = *replaced
+	return nil
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+func TestSaveBacksUp(t *testing.T) {
+	d, saved := saveText(t, twoBoards)
+	if err := d.MoveTaskByID("a1", 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	// the save of the moved task is less than backupInterval after the first one
+	backups, err := d.GetBackups()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("GetBackups() = %v, %v, want one backup", backups, err)
+	}
+	if content, _ := files.ReadFile(backups[0].Path); !slices.Equal(content, saved) {
+		t.Errorf("backup holds %q, want the boards as they were read %q", content, saved)
+	}
+}
+
+func TestRestoreBackupFile(t *testing.T) {
+	d, saved := saveText(t, twoBoards)
+	backups, _ := d.GetBackups()
+	// the board file need not be a board
+	changed := []string{"---", "not: [closed", "# b"}
+	if err := files.WriteFile(changed, "seiban.md"); err != nil {
+		t.Fatal(err)
+	}
+	// the names of the backups hold the time to the millisecond
+	time.Sleep(2 * time.Millisecond)
+	if err := RestoreBackupFile("seiban.md", backups[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got := files.OpenFile("seiban.md"); !slices.Equal(got, saved) {
+		t.Errorf("file restored as %q, want %q", got, saved)
+	}
+	// the board file was backed up before it was restored
+	backups, _ = d.GetBackups()
+	if len(backups) != 2 {
+		t.Fatalf("%v backups, want two", len(backups))
+	}
+	if content, _ := files.ReadFile(backups[0].Path); !slices.Equal(content, changed) {
+		t.Errorf("newest backup holds %q, want %q", content, changed)
+	}
+}
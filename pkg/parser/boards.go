@@ -0,0 +1,108 @@
+package parser
+
+// represents a board: its name, a slice of list and the lines under its "# " line
+// that are not part of the board format.
+type Board struct {
+	boardName  string
+	lists      []List
+	extraLines []string
+}
+
+// returns the active board. a file without a "# " line has one board without a name.
+func (d *Data) board() *Board {
+	if len(d.boards) == 0 {
+		d.boards = append(d.boards, Board{})
+	}
+	return &d.boards[d.boardIdx]
+}
+
+// returns the count of boards
+func (d *Data) GetBoardCount() int {
+	d.board()
+	return len(d.boards)
+}
+
+// returns a list of all the board names
+func (d *Data) GetBoardNames() []string {
+	var boardNames []string
+	for idx := range d.GetBoardCount() {
+		boardNames = append(boardNames, d.boards[idx].boardName)
+	}
+	return boardNames
+}
+
+// returns the index of the active board
+func (d *Data) GetActiveBoardIdx() int {
+	return d.boardIdx
+}
+
+// makes a board the one the list and task methods work on
+func (d *Data) SetActiveBoard(boardIdx int) error {
+	if err := checkBounds(boardIdx, d.GetBoardCount()); err != nil {
+		return err
+	}
+	d.boardIdx = boardIdx
+	return nil
+}
+
+// returns the list names of a board
+func (d *Data) GetBoardListNames(boardIdx int) ([]string, error) {
+	if err := checkBounds(boardIdx, d.GetBoardCount()); err != nil {
+		return nil, err
+	}
+	var listNames []string
+	for _, list := range d.boards[boardIdx].lists {
+		listNames = append(listNames, list.listTitle)
+	}
+	return listNames, nil
+}
+
+// returns the task count of a list of a board
+func (d *Data) GetBoardTaskCount(boardIdx, listIdx int) (int, error) {
+	if err := checkBounds(boardIdx, d.GetBoardCount()); err != nil {
+		return 0, err
+	}
+	lists := d.boards[boardIdx].lists
+	if err := checkBounds(listIdx, len(lists)); err != nil {
+		return 0, err
+	}
+	return len(lists[listIdx].listItems), nil
+}
+
+// moves the task with the given id, from whichever board holds it,
+// to a position in a list of another board. a task moved out of the done
+// list to a list that is not the done list of its new board is marked as
+// not done, it is returned as it was, see RestoreTaskDone.
+func (d *Data) MoveTaskToBoard(id string, destBoardIdx, destListIdx, destTaskIdx int) ([]ListItem, error) {
+	sourceBoardIdx, sourceListIdx, sourceTaskIdx, err := d.findTaskInFile(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBounds(destBoardIdx, d.GetBoardCount()); err != nil {
+		return nil, err
+	}
+	destBoard := &d.boards[destBoardIdx]
+	if err := checkBounds(destListIdx, len(destBoard.lists)); err != nil {
+		return nil, err
+	}
+	destList := &destBoard.lists[destListIdx]
+	destTaskCount := len(destList.listItems)
+	if sourceBoardIdx == destBoardIdx && sourceListIdx == destListIdx {
+		destTaskCount--
+	}
+	if err := checkBounds(destTaskIdx, destTaskCount+1); err != nil {
+		return nil, err
+	}
+	fromDoneList := sourceListIdx == d.doneListIdx(&d.boards[sourceBoardIdx])
+	sourceList := &d.boards[sourceBoardIdx].lists[sourceListIdx]
+	task := []ListItem{sourceList.listItems[sourceTaskIdx]}
+	var undone []ListItem
+	if fromDoneList && destListIdx != d.doneListIdx(destBoard) {
+		undone = clearDone(task)
+	}
+	sourceList.listItems = append(sourceList.listItems[:sourceTaskIdx], sourceList.listItems[sourceTaskIdx+1:]...)
+	destList.listItems = append(destList.listItems, ListItem{})
+	copy(destList.listItems[destTaskIdx+1:], destList.listItems[destTaskIdx:])
+	destList.listItems[destTaskIdx] = task[0]
+	return undone, nil
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const twoBoards = "# work\n## TODO\n- one <!-- id:a1 -->\n## DONE\n# home\n## TODO\n- two <!-- id:b2 -->\n- three <!-- id:c3 -->\n"
+
+func TestBoards(t *testing.T) {
+	d, saved := saveText(t, twoBoards)
+	if names := d.GetBoardNames(); !slices.Equal(names, []string{"work", "home"}) {
+		t.Errorf("boards = %q", names)
+	}
+	if err := d.SetActiveBoard(1); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := d.GetTaskCount(0); count != 2 {
+		t.Errorf("task count of home = %v, want 2", count)
+	}
+	if listNames, _ := d.GetBoardListNames(0); !slices.Equal(listNames, []string{"TODO", "DONE"}) {
+		t.Errorf("lists of work = %q", listNames)
+	}
+	reloaded := &Data{}
+	if err := reloaded.ParseData(saved); err != nil {
+		t.Fatal(err)
+	}
+	if names := reloaded.GetBoardNames(); !slices.Equal(names, []string{"work", "home"}) {
+		t.Errorf("boards read back = %q\n%v", names, strings.Join(saved, "\n"))
+	}
+}
+
+func TestMoveTaskToBoard(t *testing.T) {
+	d, _ := saveText(t, twoBoards)
+	// the task is found on any board, the active one is work
+	if _, err := d.MoveTaskToBoard("c3", 0, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if listIdx, taskIdx, err := d.FindTask("c3"); err != nil || listIdx != 1 || taskIdx != 0 {
+		t.Errorf("c3 is at %v/%v (%v), want 1/0 of work", listIdx, taskIdx, err)
+	}
+	if count, _ := d.GetBoardTaskCount(1, 0); count != 1 {
+		t.Errorf("task count of home = %v, want 1", count)
+	}
+	if _, err := d.MoveTaskToBoard("a1", 2, 0, 0); err == nil {
+		t.Error("moving a task to a board that does not exist succeeded")
+	}
+}
@@ -0,0 +1,88 @@
+package parser
+
+// kinds of the lines under a task
+const (
+	descriptionLine = iota
+	subtaskLine
+	extraLine
+)
+
+// a line under a task as it was written: a line of its description, a
+// subtask or a line that is not part of the board format. the description
+// and subtasks are kept in their fields, the lines of the body only keep
+// where they were so that the extra lines stay between them.
+type bodyLine struct {
+	kind int
+	// the line, for an extra line
+	text string
+}
+
+// keeps a line that is not part of the board format under the task, after
+// the lines read so far. the empty lines before it are kept too, unless it
+// is the first extra line of the task.
+func (i *ListItem) addExtraLine(rawLine string, emptyLines int) {
+	if len(i.notes()) > 0 {
+		for range emptyLines {
+			i.body = append(i.body, bodyLine{kind: extraLine})
+		}
+	}
+	i.body = append(i.body, bodyLine{kind: extraLine, text: rawLine})
+}
+
+// returns the lines under the task that are not part of the board format
+func (i ListItem) notes() []string {
+	var notes []string
+	for _, line := range i.body {
+		if line.kind == extraLine {
+			notes = append(notes, line.text)
+		}
+	}
+	return notes
+}
+
+// returns the lines under the task, given the lines its description and
+// subtasks are written as: every one where a line of its kind was read,
+// the extra lines in between. description lines or subtasks added since go
+// after the last one read, or where they go in a new task: the description
+// first, then the subtasks.
+func (i ListItem) bodyLines(description, subtasks []string) []string {
+	lastDescription, lastSubtask := -1, -1
+	for idx, line := range i.body {
+		switch line.kind {
+		case descriptionLine:
+			lastDescription = idx
+		case subtaskLine:
+			lastSubtask = idx
+		}
+	}
+	var lines []string
+	// adds the next line of a kind, or all that are left
+	next := func(kindLines *[]string, all bool) {
+		count := min(1, len(*kindLines))
+		if all {
+			count = len(*kindLines)
+		}
+		lines = append(lines, (*kindLines)[:count]...)
+		*kindLines = (*kindLines)[count:]
+	}
+	if lastDescription < 0 {
+		next(&description, true)
+		if lastSubtask < 0 {
+			next(&subtasks, true)
+		}
+	}
+	for idx, line := range i.body {
+		switch line.kind {
+		case descriptionLine:
+			next(&description, idx == lastDescription)
+			if idx == lastDescription && lastSubtask < 0 {
+				next(&subtasks, true)
+			}
+		case subtaskLine:
+			next(&subtasks, idx == lastSubtask)
+		default:
+			lines = append(lines, line.text)
+		}
+	}
+	return lines
+}
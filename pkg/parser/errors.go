@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// an error found while parsing a board file
+type ParseError struct {
+	// name of the file, as given on the command line
+	File string
+	// 1-based line and column of the offending text
+	Line   int
+	Column int
+	// the offending line, without the indentation
+	Text    string
+	Message string
+	// how the line can be fixed, may be empty
+	Hint string
+	// set for a line that is kept as it is, it does not stop the file from loading
+	Warning bool
+}
+
+// returns the error in the form compilers use, e.g. "seiban.md:12:3: warning: task outside of any list"
+func (e *ParseError) Error() string {
+	if e.Warning {
+		return fmt.Sprintf("%v:%v:%v: warning: %v", e.File, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%v:%v:%v: %v", e.File, e.Line, e.Column, e.Message)
+}
+
+// returns the error followed by the offending line and the hint
+func (e *ParseError) Detail() string {
+	detail := e.Error()
+	if len(e.Text) > 0 {
+		detail += "\n\t" + e.Text
+	}
+	if len(e.Hint) > 0 {
+		detail += "\n\thint: " + e.Hint
+	}
+	return detail
+}
+
+// all the errors found in a file, in the order of their lines
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	var details []string
+	for _, parseError := range e {
+		details = append(details, parseError.Detail())
+	}
+	return strings.Join(details, "\n")
+}
+
+// creates a warning for a line of the file, lineNumber is 0-based
+func newParseWarning(lineNumber int, rawLine, message, hint string) *ParseError {
+	warning := newParseError(lineNumber, rawLine, message, hint)
+	warning.Warning = true
+	return warning
+}
+
+// creates an error for a line of the file, lineNumber is 0-based
+func newParseError(lineNumber int, rawLine, message, hint string) *ParseError {
+	text := strings.TrimLeft(rawLine, " \t")
+	return &ParseError{
+		Line:    lineNumber + 1,
+		Column:  len(rawLine) - len(text) + 1,
+		Text:    strings.TrimRight(text, " \t"),
+		Message: message,
+		Hint:    hint,
+	}
+}
+
+// sorts the warnings of the data by line and sets their file
+func (d *Data) finishWarnings() {
+	slices.SortStableFunc(d.warnings, func(a, b *ParseError) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+	for _, warning := range d.warnings {
+		warning.File = d.fileName
+	}
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+func TestParseErrorFormat(t *testing.T) {
+	parseError := newParseError(11, "\t\t- lost task  ", "task outside of any list", `add a "## " line above it`)
+	parseError.File = "seiban.md"
+	if got, want := parseError.Error(), "seiban.md:12:3: task outside of any list"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	want := "seiban.md:12:3: task outside of any list\n\t- lost task\n\thint: add a \"## \" line above it"
+	if got := parseError.Detail(); got != want {
+		t.Errorf("Detail() = %q, want %q", got, want)
+	}
+	parseError.Warning = true
+	if got, want := parseError.Error(), "seiban.md:12:3: warning: task outside of any list"; got != want {
+		t.Errorf("Error() of a warning = %q, want %q", got, want)
+	}
+}
+
+func TestParseWarnings(t *testing.T) {
+	t.Chdir(t.TempDir())
+	d := &Data{}
+	d.SetFileName("seiban.md")
+	if err := d.ParseData(strings.Split("# b\n- lost\n## TODO\n  > lost too\n- one\n", "\n")); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, warning := range d.Warnings() {
+		got = append(got, warning.Error())
+	}
+	// all of them, in the order of their lines
+	want := []string{"seiban.md:2:1: warning: task outside of any list", "seiban.md:4:3: warning: description outside of any task"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings = %q, want %q", got, want)
+	}
+	// the lines are kept as notes
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if text := strings.Join(files.OpenFile("seiban.md"), "\n"); !strings.Contains(text, "- lost\n") || !strings.Contains(text, "> lost too\n") {
+		t.Errorf("saved as\n%v", text)
+	}
+}
+
+func TestParseWarningsFormat(t *testing.T) {
+	d := &Data{}
+	d.SetFileName("seiban.md")
+	if err := d.ParseData(strings.Split("---\narchive: soon\n---\n# b\n- lost\n## TODO\n", "\n")); err != nil {
+		t.Fatal(err)
+	}
+	// as seiban prints them to stderr, the lines of the front matter count too
+	want := "seiban.md:2:1: warning: archive must be a number of days, got \"soon\"\n\tarchive: soon\n" +
+		"\thint: write the number of days done tasks are kept, e.g. \"archive: 14\"\n" +
+		"seiban.md:5:1: warning: task outside of any list\n\t- lost\n\thint: add a \"## \" list heading above it"
+	if got := d.Warnings().Error(); got != want {
+		t.Errorf("warnings:\n%v\nwant:\n%v", got, want)
+	}
+}
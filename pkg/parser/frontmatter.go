@@ -0,0 +1,393 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const frontMatterDelimiter = "---"
+
+// per-board configuration, read from the front matter at the top of the file:
+//
+//	---
+//	done: DONE
+//	wip:
+//	  DOING: 3
+//	tags: [team]
+//	theme:
+//	  border: wheat
+//	keys:
+//	  done: x
+//	autocommit: 10
+//	archive: 14
+//	---
+type Config struct {
+	// title of the list that holds the done tasks, the last list if empty
+	DoneList string
+	// maximum number of tasks of a list, by list title
+	WIPLimits map[string]int
+	// tags given to new tasks
+	DefaultTags []string
+	// colors of the ui, by the name of the element (border, title, text, ...)
+	Theme map[string]string
+	// keys of the actions of the board, by the name of the action (done, add, ...)
+	Keys map[string]string
+	// commit the board file to the git repository it is in when seiban quits
+	AutoCommit bool
+	// number of commands after which the board file is committed as well, 0 for none
+	AutoCommitEvery int
+	// number of days a task stays in the done list before it is archived, 0 to archive all of them
+	ArchiveAfter int
+}
+
+// returns the configuration read from the front matter
+func (d *Data) GetConfig() Config {
+	return d.config
+}
+
+// returns the index of the list done tasks are moved to
+func (d *Data) GetDoneListIdx() int {
+	return d.doneListIdx(d.board())
+}
+
+// returns the index of the done list of a board, -1 if it has no lists
+func (d *Data) doneListIdx(board *Board) int {
+	for listIdx, list := range board.lists {
+		if len(d.config.DoneList) > 0 && strings.EqualFold(list.listTitle, d.config.DoneList) {
+			return listIdx
+		}
+	}
+	return len(board.lists) - 1
+}
+
+// returns the wip limit of a list, false if the list has none
+func (d *Data) GetWIPLimit(listIdx int) (int, bool) {
+	list, err := d.GetList(listIdx)
+	if err != nil {
+		return 0, false
+	}
+	for listTitle, limit := range d.config.WIPLimits {
+		if strings.EqualFold(listTitle, list.listTitle) {
+			return limit, true
+		}
+	}
+	return 0, false
+}
+
+// reads the front matter block from the start of the file, if there is one,
+// and returns the lines after it. the lines of the block are kept as they
+// are so that Save writes the block back unchanged. errors in the block
+// leave the values they concern unset, they are added to the warnings.
+func (d *Data) parseFrontMatter(fileContent []string) []string {
+	if len(fileContent) == 0 || strings.TrimSpace(fileContent[0]) != frontMatterDelimiter {
+		return fileContent
+	}
+	endLineNumber := -1
+	for lineNumber := 1; lineNumber < len(fileContent); lineNumber++ {
+		if strings.TrimSpace(fileContent[lineNumber]) == frontMatterDelimiter {
+			endLineNumber = lineNumber
+			break
+		}
+	}
+	if endLineNumber < 0 {
+		// not closed, so it is not front matter
+		return fileContent
+	}
+	d.warnings = append(d.warnings, d.setFrontMatter(fileContent[:endLineNumber+1])...)
+	return fileContent[endLineNumber+1:]
+}
+
+// keeps the lines of a front matter block, the delimiters included, and
+// reads the configuration from them. returns the warnings about the block.
+func (d *Data) setFrontMatter(frontMatter []string) []*ParseError {
+	block := frontMatter[1 : len(frontMatter)-1]
+	values, keyLines, warnings := parseFrontMatterValues(block)
+	config, configWarnings := configFromValues(values, block, keyLines)
+	d.frontMatter = frontMatter
+	d.config = config
+	if _, ok := values[obsidianFrontMatterKey]; ok {
+		d.format = FormatObsidian
+	}
+	return slices.Concat(warnings, configWarnings)
+}
+
+// returns a copy of the lines of the front matter block, see RestoreListTitle and RestoreList
+func (d *Data) FrontMatter() []string {
+	return slices.Clone(d.frontMatter)
+}
+
+// makes the done list and the wip limits of the front matter that refer to a
+// list by its title refer to it by a new title, or removes them if the new
+// title is empty. nothing is changed while a list of another board has the
+// title, they refer to that list too. the lines are changed in place, the
+// rest of the block is kept as it is.
+func (d *Data) renameFrontMatterList(list *List, listTitle string) {
+	if len(d.frontMatter) == 0 {
+		return
+	}
+	for boardIdx := range d.boards {
+		for listIdx := range d.boards[boardIdx].lists {
+			other := &d.boards[boardIdx].lists[listIdx]
+			if other != list && strings.EqualFold(other.listTitle, list.listTitle) {
+				return
+			}
+		}
+	}
+	// returns the title as it is written in place of value, in the same quotes
+	retitle := func(value string) string {
+		if len(value) >= 2 && unquote(value) != value {
+			return value[:1] + listTitle + value[:1]
+		}
+		return listTitle
+	}
+	matches := func(value string) bool {
+		return strings.EqualFold(unquote(strings.TrimSpace(value)), list.listTitle)
+	}
+	block := d.frontMatter[1 : len(d.frontMatter)-1]
+	frontMatter := []string{d.frontMatter[0]}
+	currentKey := ""
+	for _, rawLine := range block {
+		line, comment, hasComment := strings.Cut(rawLine, " #")
+		key, value, found := strings.Cut(line, ":")
+		indented := indentWidth(rawLine) > 0
+		if !indented {
+			currentKey = strings.TrimSpace(key)
+		}
+		trimmedValue := strings.TrimSpace(value)
+		switch {
+		case !found:
+		case !indented && currentKey == "done" && matches(value):
+			if len(listTitle) == 0 {
+				continue
+			}
+			value = " " + retitle(trimmedValue)
+		case !indented && currentKey == "wip" && strings.HasPrefix(trimmedValue, "{") && strings.HasSuffix(trimmedValue, "}"):
+			var pairs []string
+			for _, pair := range strings.Split(trimmedValue[1:len(trimmedValue)-1], ",") {
+				pairKey, pairValue, _ := strings.Cut(pair, ":")
+				switch {
+				case !matches(pairKey):
+					pairs = append(pairs, strings.TrimSpace(pair))
+				case len(listTitle) > 0:
+					pairs = append(pairs, retitle(strings.TrimSpace(pairKey))+": "+strings.TrimSpace(pairValue))
+				}
+			}
+			value = " {" + strings.Join(pairs, ", ") + "}"
+		case indented && currentKey == "wip" && matches(key):
+			if len(listTitle) == 0 {
+				continue
+			}
+			key = rawLine[:len(rawLine)-len(strings.TrimLeft(rawLine, " \t"))] + retitle(strings.TrimSpace(key))
+		default:
+			frontMatter = append(frontMatter, rawLine)
+			continue
+		}
+		line = key + ":" + value
+		if hasComment {
+			line += " #" + comment
+		}
+		frontMatter = append(frontMatter, line)
+	}
+	frontMatter = append(frontMatter, d.frontMatter[len(d.frontMatter)-1])
+	d.setFrontMatter(frontMatter)
+}
+
+// parses the simple subset of yaml used in the front matter: "key: value"
+// pairs, inline lists ("[a, b]") and maps ("{a: 1, b: 2}") and one level of
+// nested "key: value" pairs or "- value" items under a "key:" line. "#"
+// starts a comment. also returns the index of the line of every key ("key"
+// or "key.nested"), and warnings for the lines that are none of these.
+func parseFrontMatterValues(lines []string) (map[string]any, map[string]int, []*ParseError) {
+	values := make(map[string]any)
+	keyLines := make(map[string]int)
+	var warnings []*ParseError
+	// the block starts on the second line of the file
+	addWarning := func(lineNumber int, message, hint string) {
+		warnings = append(warnings, newParseWarning(lineNumber+1, lines[lineNumber], message, hint))
+	}
+	currentKey := ""
+	for lineNumber, rawLine := range lines {
+		line, _, _ := strings.Cut(rawLine, " #")
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if indentWidth(rawLine) == 0 {
+			key, value, found := strings.Cut(line, ":")
+			if !found {
+				currentKey = ""
+				addWarning(lineNumber, "expected \"key: value\" in front matter", "write the setting as \"key: value\"")
+				continue
+			}
+			currentKey = strings.TrimSpace(key)
+			keyLines[currentKey] = lineNumber
+			value = strings.TrimSpace(value)
+			if len(value) > 0 {
+				values[currentKey] = parseFrontMatterValue(value)
+			}
+			if nested, ok := values[currentKey].(map[string]string); ok {
+				for key := range nested {
+					keyLines[currentKey+"."+key] = lineNumber
+				}
+			}
+			continue
+		}
+		if len(currentKey) == 0 {
+			addWarning(lineNumber, "indented line without a key in front matter", "put it under a \"key:\" line or remove the indentation")
+			continue
+		}
+		if item, found := strings.CutPrefix(line, "- "); found {
+			items, _ := values[currentKey].([]string)
+			values[currentKey] = append(items, unquote(strings.TrimSpace(item)))
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			addWarning(lineNumber, "expected \"key: value\" in front matter", "write the setting as \"key: value\" or \"- value\"")
+			continue
+		}
+		nested, ok := values[currentKey].(map[string]string)
+		if !ok {
+			nested = make(map[string]string)
+			values[currentKey] = nested
+		}
+		key = unquote(strings.TrimSpace(key))
+		nested[key] = unquote(strings.TrimSpace(value))
+		keyLines[currentKey+"."+key] = lineNumber
+	}
+	return values, keyLines, warnings
+}
+
+// parses a scalar, an inline list or an inline map
+func parseFrontMatterValue(value string) any {
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		nested := make(map[string]string)
+		for _, pair := range strings.Split(value[1:len(value)-1], ",") {
+			if key, value, found := strings.Cut(pair, ":"); found {
+				nested[unquote(strings.TrimSpace(key))] = unquote(strings.TrimSpace(value))
+			}
+		}
+		return nested
+	}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var items []string
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			if item = unquote(strings.TrimSpace(item)); len(item) > 0 {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return unquote(value)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// builds the configuration from the parsed values, unknown keys are ignored.
+// lines and keyLines locate the values that are not valid, they are left
+// unset and returned as warnings.
+func configFromValues(values map[string]any, lines []string, keyLines map[string]int) (Config, []*ParseError) {
+	var config Config
+	var warnings []*ParseError
+	addWarning := func(key, message, hint string) {
+		lineNumber := keyLines[key]
+		warnings = append(warnings, newParseWarning(lineNumber+1, lines[lineNumber], message, hint))
+	}
+	// the keys whose value must be nested "key: value" pairs
+	for _, key := range []string{"wip", "theme", "keys"} {
+		if _, ok := values[key]; ok {
+			if _, ok := values[key].(map[string]string); !ok {
+				addWarning(key, fmt.Sprintf("%v must be \"key: value\" pairs", key),
+					fmt.Sprintf("write them indented under \"%v:\", one per line, or as \"%v: {key: value}\"", key, key))
+			}
+		}
+	}
+	switch done := values["done"].(type) {
+	case string:
+		config.DoneList = done
+	case nil:
+	default:
+		addWarning("done", "done must be the title of a list", "write the title of the list of done tasks, e.g. \"done: DONE\"")
+	}
+	if wip, ok := values["wip"].(map[string]string); ok {
+		config.WIPLimits = make(map[string]int)
+		for listTitle, limitText := range wip {
+			limit, err := strconv.Atoi(limitText)
+			if err != nil || limit < 1 {
+				addWarning("wip."+listTitle,
+					fmt.Sprintf("wip limit of %q must be a positive number, got %q", listTitle, limitText),
+					fmt.Sprintf("write the limit as a whole number, e.g. \"%v: 3\"", listTitle))
+				continue
+			}
+			config.WIPLimits[listTitle] = limit
+		}
+	}
+	switch tags := values["tags"].(type) {
+	case []string:
+		config.DefaultTags = ParseTags(strings.Join(tags, " "))
+	case string:
+		config.DefaultTags = ParseTags(tags)
+	}
+	if theme, ok := values["theme"].(map[string]string); ok {
+		config.Theme = theme
+	}
+	if keys, ok := values["keys"].(map[string]string); ok {
+		config.Keys = make(map[string]string)
+		// in the order of their lines, of two actions given the same key the later one is left out
+		actions := slices.SortedFunc(maps.Keys(keys), func(a, b string) int {
+			return cmp.Compare(keyLines["keys."+a], keyLines["keys."+b])
+		})
+		keyActions := make(map[string]string)
+		for _, action := range actions {
+			key := keys[action]
+			switch {
+			case utf8.RuneCountInString(key) != 1:
+				addWarning("keys."+action, fmt.Sprintf("key of %q must be one character, got %q", action, key),
+					fmt.Sprintf("write the key of the action, e.g. \"%v: x\"", action))
+			case len(keyActions[key]) > 0:
+				addWarning("keys."+action, fmt.Sprintf("key %q of %q is the key of %q too", key, action, keyActions[key]),
+					"give every action its own key")
+			default:
+				config.Keys[action] = key
+				keyActions[key] = action
+			}
+		}
+	}
+	if autoCommit, ok := values["autocommit"].(string); ok {
+		// "quit", "off" or the number of commands between commits
+		every, err := strconv.Atoi(autoCommit)
+		switch {
+		case autoCommit == "quit":
+			config.AutoCommit = true
+		case autoCommit == "off":
+		case err == nil && every > 0:
+			config.AutoCommit = true
+			config.AutoCommitEvery = every
+		default:
+			addWarning("autocommit",
+				fmt.Sprintf("autocommit must be \"quit\", \"off\" or a positive number, got %q", autoCommit),
+				"write \"autocommit: quit\" to commit when seiban quits, or e.g. \"autocommit: 10\" to commit every 10 changes as well")
+		}
+	}
+	if archiveAfter, ok := values["archive"].(string); ok {
+		days, err := strconv.Atoi(archiveAfter)
+		if err != nil || days < 0 {
+			addWarning("archive",
+				fmt.Sprintf("archive must be a number of days, got %q", archiveAfter),
+				"write the number of days done tasks are kept, e.g. \"archive: 14\"")
+		} else {
+			config.ArchiveAfter = days
+		}
+	}
+	return config, warnings
+}
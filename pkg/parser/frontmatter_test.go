@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFrontMatter(t *testing.T) {
+	file := "---\ndone: DOING   # the done list\nwip:\n  DOING: 2\ntags: [team, ops]\ntheme:\n  border: wheat\nkeys:\n  done: x\n---\n# b\n## TODO\n## DOING\n## DONE\n"
+	d, saved := saveText(t, file)
+	config := d.GetConfig()
+	if config.DoneList != "DOING" || config.Keys["done"] != "x" || config.Theme["border"] != "wheat" {
+		t.Errorf("config = %+v", config)
+	}
+	if !slices.Equal(config.DefaultTags, []string{"team", "ops"}) {
+		t.Errorf("tags = %q", config.DefaultTags)
+	}
+	if d.GetDoneListIdx() != 1 {
+		t.Errorf("done list = %v, want 1", d.GetDoneListIdx())
+	}
+	if limit, ok := d.GetWIPLimit(1); !ok || limit != 2 {
+		t.Errorf("wip limit = %v, %v, want 2", limit, ok)
+	}
+	if _, ok := d.GetWIPLimit(0); ok {
+		t.Error("TODO has a wip limit")
+	}
+	// the block is written back as it was
+	if frontMatter := strings.Join(saved[:10], "\n"); !strings.HasPrefix(file, frontMatter+"\n# b") {
+		t.Errorf("saved as\n%v", strings.Join(saved, "\n"))
+	}
+}
+
+func TestFrontMatterDefaults(t *testing.T) {
+	d, _ := saveText(t, "# b\n## TODO\n## DOING\n## DONE\n")
+	if d.GetDoneListIdx() != 2 {
+		t.Errorf("done list = %v, want the last one", d.GetDoneListIdx())
+	}
+}
+
+func TestInvalidFrontMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		// the lines of the warnings
+		lines []int
+	}{
+		{"invalid wip limit", "---\nwip:\n  TODO: many\n---\n# b\n## TODO\n", []int{3}},
+		{"invalid autocommit", "---\nautocommit: sometimes\n---\n# b\n## TODO\n", []int{2}},
+		{"key of two actions", "---\nkeys:\n  done: x\n  add: x\n---\n# b\n## TODO\n", []int{4}},
+		{"key longer than a character", "---\nkeys:\n  done: xx\n---\n# b\n## TODO\n", []int{3}},
+		{"line numbers after the front matter", "---\ndone: TODO\n---\n# b\n- lost\n## TODO\n", []int{5}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := &Data{}
+			// the board is loaded anyway
+			if err := d.ParseData(strings.Split(test.file, "\n")); err != nil {
+				t.Fatal(err)
+			}
+			if d.GetListCount() != 1 {
+				t.Errorf("lists = %q", d.GetListNames())
+			}
+			var lines []int
+			for _, warning := range d.Warnings() {
+				if !warning.Warning {
+					t.Errorf("%v is not a warning", warning)
+				}
+				lines = append(lines, warning.Line)
+			}
+			if !slices.Equal(lines, test.lines) {
+				t.Errorf("warnings %v, want them on lines %v", d.Warnings(), test.lines)
+			}
+		})
+	}
+}
+
+func TestRenameListInFrontMatter(t *testing.T) {
+	file := "---\ndone: DONE # finished work\nwip: {DOING: 2, DONE: 5}\n---\n\n# b\n\n## DOING\n\n\n## DONE\n\n"
+	d, storage := loadText(t, file)
+	prevFrontMatter := d.FrontMatter()
+	if err := d.RenameList(1, "FINISHED"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"---", "done: FINISHED # finished work", "wip: {DOING: 2, FINISHED: 5}", "---"}
+	if got := d.FrontMatter(); !slices.Equal(got, want) {
+		t.Errorf("front matter = %q, want %q", got, want)
+	}
+	if d.GetDoneListIdx() != 1 {
+		t.Errorf("done list = %v, want 1", d.GetDoneListIdx())
+	}
+	if err := d.RestoreListTitle(1, "DONE", prevFrontMatter); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(storage.Content(), "\n"); got != file {
+		t.Errorf("restored as\n%q\nwant\n%q", got, file)
+	}
+}
+
+func TestRemoveListInFrontMatter(t *testing.T) {
+	d, _ := loadText(t, "---\nwip:\n  DOING: 2\n  DONE: 5\n---\n\n# b\n\n## DOING\n\n\n## DONE\n\n")
+	if _, err := d.RemoveList(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.GetWIPLimit(0); !ok {
+		t.Error("the wip limit of DONE was removed")
+	}
+	want := []string{"---", "wip:", "  DONE: 5", "---"}
+	if got := d.FrontMatter(); !slices.Equal(got, want) {
+		t.Errorf("front matter = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+const (
+	taskIDPrefix = "<!-- id:"
+	taskIDSuffix = " -->"
+)
+
+// removes the trailing "<!-- id:... -->" comment from the text of a "- " line
+// and returns the id written in it, empty if there is none.
+func parseTaskID(text string) (rest, id string) {
+	trimmed := strings.TrimRight(text, " ")
+	if !strings.HasSuffix(trimmed, strings.TrimSpace(taskIDSuffix)) {
+		return text, ""
+	}
+	idStartIndex := strings.LastIndex(trimmed, taskIDPrefix)
+	if idStartIndex < 0 {
+		return text, ""
+	}
+	id = trimmed[idStartIndex+len(taskIDPrefix) : len(trimmed)-len(strings.TrimSpace(taskIDSuffix))]
+	id = strings.TrimSpace(id)
+	if len(id) == 0 || strings.ContainsAny(id, " \t") {
+		return text, ""
+	}
+	return strings.TrimRight(trimmed[:idStartIndex], " "), id
+}
+
+// returns the comment holding the id of the item, as written at the end of its line
+func (i ListItem) idComment() string {
+	if len(i.ID) == 0 {
+		return ""
+	}
+	return taskIDPrefix + i.ID + taskIDSuffix
+}
+
+// returns a new id of 8 hex digits that no task of the file uses yet. ids
+// are random so that tasks added on different branches rarely get the same
+// one, ids of older files (e.g. 4 digits) are kept as they are.
+func (d *Data) NewTaskID() string {
+	for {
+		id := fmt.Sprintf("%08x", rand.Uint32())
+		if _, _, _, err := d.findTaskInFile(id); err != nil {
+			return id
+		}
+	}
+}
+
+// gives every task without an id (or with the id of an earlier task) a new one
+func (d *Data) assignTaskIDs() {
+	seen := make(map[string]bool)
+	d.assignedIDs = make(map[string]bool)
+	for boardIdx := range d.boards {
+		lists := d.boards[boardIdx].lists
+		for listIdx := range lists {
+			for taskIdx := range lists[listIdx].listItems {
+				task := &lists[listIdx].listItems[taskIdx]
+				if len(task.ID) == 0 || seen[task.ID] {
+					task.ID = d.NewTaskID()
+					d.assignedIDs[task.ID] = true
+				}
+				seen[task.ID] = true
+			}
+		}
+	}
+}
+
+// returns the board, list and task index of the task with the given id, looking in every board
+func (d *Data) findTaskInFile(id string) (boardIdx, listIdx, taskIdx int, err error) {
+	for boardIdx, board := range d.boards {
+		for listIdx, list := range board.lists {
+			for taskIdx, task := range list.listItems {
+				if task.ID == id {
+					return boardIdx, listIdx, taskIdx, nil
+				}
+			}
+		}
+	}
+	return 0, 0, 0, fmt.Errorf("Task not found: %v", id)
+}
+
+// returns the list and task index of the task with the given id in the active board
+func (d *Data) FindTask(id string) (listIdx, taskIdx int, err error) {
+	for listIdx, list := range d.board().lists {
+		for taskIdx, task := range list.listItems {
+			if task.ID == id {
+				return listIdx, taskIdx, nil
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("Task not found: %v", id)
+}
+
+// returns the task with the given id
+func (d *Data) GetTaskByID(id string) (*ListItem, error) {
+	listIdx, taskIdx, err := d.FindTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetTask(listIdx, taskIdx)
+}
+
+// edits the task with the given id, the id itself is kept
+func (d *Data) EditTaskByID(id string, editedTask ListItem) error {
+	listIdx, taskIdx, err := d.FindTask(id)
+	if err != nil {
+		return err
+	}
+	editedTask.ID = id
+	return d.EditTask(listIdx, taskIdx, editedTask)
+}
+
+// removes the task with the given id
+func (d *Data) RemoveTaskByID(id string) (ListItem, error) {
+	listIdx, taskIdx, err := d.FindTask(id)
+	if err != nil {
+		return ListItem{}, err
+	}
+	return d.RemoveTask(listIdx, taskIdx)
+}
+
+// moves the task with the given id to a position in a list
+func (d *Data) MoveTaskByID(id string, destListIdx, destTaskIdx int) error {
+	sourceListIdx, sourceTaskIdx, err := d.FindTask(id)
+	if err != nil {
+		return err
+	}
+	destList, err := d.GetList(destListIdx)
+	if err != nil {
+		return err
+	}
+	destTaskCount := len(destList.listItems)
+	if sourceListIdx == destListIdx {
+		destTaskCount--
+	}
+	if err := checkBounds(destTaskIdx, destTaskCount+1); err != nil {
+		return err
+	}
+	sourceList := &d.board().lists[sourceListIdx]
+	task := sourceList.listItems[sourceTaskIdx]
+	sourceList.listItems = append(sourceList.listItems[:sourceTaskIdx], sourceList.listItems[sourceTaskIdx+1:]...)
+	if err := d.insertTask(destListIdx, task, destTaskIdx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// swaps the positions of two tasks given their ids
+func (d *Data) SwapTasksByID(firstID, secondID string) error {
+	firstTask, err := d.GetTaskByID(firstID)
+	if err != nil {
+		return err
+	}
+	secondTask, err := d.GetTaskByID(secondID)
+	if err != nil {
+		return err
+	}
+	*firstTask, *secondTask = *secondTask, *firstTask
+	return nil
+}
+
+// marks the task with the given id as done or not done
+func (d *Data) SetTaskDoneByID(id string, done bool) error {
+	task, err := d.GetTaskByID(id)
+	if err != nil {
+		return err
+	}
+	task.setDone(done)
+	return nil
+}
+
+// marks the task with the given id as done on the given day, or as not done,
+// as it was before (for undo) or after (for redo) SetTaskDoneByID
+func (d *Data) RestoreTaskDone(id string, done bool, doneDate time.Time) error {
+	task, err := d.GetTaskByID(id)
+	if err != nil {
+		return err
+	}
+	task.Done, task.DoneDate = done, doneDate
+	if !done {
+		task.DoneDate = time.Time{}
+	}
+	return nil
+}
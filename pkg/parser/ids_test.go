@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTaskID(t *testing.T) {
+	tests := []struct {
+		text, rest, id string
+	}{
+		{"fix login <!-- id:a1b2 -->", "fix login", "a1b2"},
+		{"fix login <!-- id:a1b2 -->  ", "fix login", "a1b2"},
+		{"fix login", "fix login", ""},
+		{"fix <!-- id:a1b2 --> login", "fix <!-- id:a1b2 --> login", ""},
+		{"fix login <!-- id: -->", "fix login <!-- id: -->", ""},
+	}
+	for _, test := range tests {
+		if rest, id := parseTaskID(test.text); rest != test.rest || id != test.id {
+			t.Errorf("parseTaskID(%q) = %q, %q, want %q, %q", test.text, rest, id, test.rest, test.id)
+		}
+	}
+}
+
+func TestAssignTaskIDs(t *testing.T) {
+	d := &Data{}
+	if err := d.ParseData(strings.Split("# b\n## TODO\n- one <!-- id:a1 -->\n- two\n- three <!-- id:a1 -->\n", "\n")); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for taskIdx := range 3 {
+		task, _ := d.GetTask(0, taskIdx)
+		if len(task.ID) == 0 || seen[task.ID] {
+			t.Errorf("task %v has the id %q", taskIdx, task.ID)
+		}
+		seen[task.ID] = true
+	}
+	if task, _ := d.GetTaskByID("a1"); task.ItemName != "one" {
+		t.Errorf("a1 is %q, want the first task", task.ItemName)
+	}
+}
+
+func TestMoveTaskByID(t *testing.T) {
+	d, _ := saveText(t, "# b\n## TODO\n- one <!-- id:a1 -->\n- two <!-- id:b2 -->\n## DONE\n- three <!-- id:c3 -->\n")
+	if err := d.MoveTaskByID("a1", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if listIdx, taskIdx, _ := d.FindTask("a1"); listIdx != 1 || taskIdx != 1 {
+		t.Errorf("a1 is at %v/%v, want 1/1", listIdx, taskIdx)
+	}
+	if err := d.MoveTask(0, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if listIdx, taskIdx, _ := d.FindTask("c3"); listIdx != 0 || taskIdx != 1 {
+		t.Errorf("c3 is at %v/%v, want 0/1", listIdx, taskIdx)
+	}
+	if err := d.MoveTaskByID("b2", 1, 5); err == nil {
+		t.Error("moving a task out of the bounds of the list succeeded")
+	}
+}
+
+func TestNewTaskID(t *testing.T) {
+	d := &Data{}
+	id := d.NewTaskID()
+	if len(id) != 8 || strings.Trim(id, "0123456789abcdef") != "" {
+		t.Errorf("NewTaskID() = %q, want 8 hex digits", id)
+	}
+}
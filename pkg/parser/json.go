@@ -0,0 +1,208 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+// keeps the boards in a JSON file, for tools that read or write boards:
+//
+//	{
+//	  "frontMatter": ["---", "done: DONE", "---"],
+//	  "boards": [{
+//	    "name": "seiban",
+//	    "lists": [{
+//	      "title": "TODO",
+//	      "tasks": [{"id": "a1b2c3d4", "name": "fix login", "priority": "high", "due": "2026-11-01", "tags": ["bug"]}]
+//	    }]
+//	  }]
+//	}
+//
+// "notes" keep the lines of a board, list or task that are not part of the
+// board format, as in the markdown file.
+type JSONStorage struct {
+	fileName string
+}
+
+func NewJSONStorage(fileName string) *JSONStorage {
+	return &JSONStorage{fileName: fileName}
+}
+
+type jsonFile struct {
+	FrontMatter []string    `json:"frontMatter,omitempty"`
+	Notes       []string    `json:"notes,omitempty"`
+	Boards      []jsonBoard `json:"boards"`
+}
+
+type jsonBoard struct {
+	Name  string     `json:"name"`
+	Notes []string   `json:"notes,omitempty"`
+	Lists []jsonList `json:"lists"`
+}
+
+type jsonList struct {
+	Title    string     `json:"title"`
+	Complete bool       `json:"complete,omitempty"`
+	Notes    []string   `json:"notes,omitempty"`
+	Tasks    []jsonTask `json:"tasks"`
+}
+
+type jsonTask struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Priority    string        `json:"priority,omitempty"`
+	Due         string        `json:"due,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Done        bool          `json:"done,omitempty"`
+	DoneDate    string        `json:"doneDate,omitempty"`
+	Subtasks    []jsonSubtask `json:"subtasks,omitempty"`
+	Notes       []string      `json:"notes,omitempty"`
+}
+
+type jsonSubtask struct {
+	Name string `json:"name"`
+	Done bool   `json:"done,omitempty"`
+}
+
+func (s *JSONStorage) Load(d *Data) (string, error) {
+	if !files.CheckFile(s.fileName) {
+		return "", fmt.Errorf("Cannot open file %v: it does not exist", s.fileName)
+	}
+	fileContent, hash, err := files.ReadFileWithHash(s.fileName)
+	if err != nil {
+		return "", err
+	}
+	var file jsonFile
+	if err := json.Unmarshal([]byte(strings.Join(fileContent, "\n")), &file); err != nil {
+		return "", fmt.Errorf("Error in file %v: %v", s.fileName, err)
+	}
+	return hash, d.fromJSON(file)
+}
+
+// replaces the file, see files.WriteFile
+func (s *JSONStorage) Save(d *Data) (string, error) {
+	content, err := json.MarshalIndent(d.toJSON(), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	fileContent := strings.Split(string(content), "\n")
+	if err := files.WriteFile(fileContent, s.fileName); err != nil {
+		return "", err
+	}
+	return files.HashLines(fileContent), nil
+}
+
+// returns the hash of the bytes of the file
+func (s *JSONStorage) Hash() (string, error) {
+	return files.HashFile(s.fileName)
+}
+
+func (s *JSONStorage) Watch(onChange func()) (func(), error) {
+	return watchFile(s.fileName, onChange)
+}
+
+// fills the data, which is empty, with the boards of the file
+func (d *Data) fromJSON(file jsonFile) error {
+	if len(file.FrontMatter) > 0 {
+		if rest := d.parseFrontMatter(file.FrontMatter); len(rest) > 0 {
+			return fmt.Errorf("Error in front matter of file %v: it must be one \"---\" block", d.fileName)
+		}
+		d.finishWarnings()
+	}
+	d.preamble = file.Notes
+	for _, jsonBoard := range file.Boards {
+		board := Board{boardName: jsonBoard.Name, extraLines: jsonBoard.Notes}
+		for _, jsonList := range jsonBoard.Lists {
+			list := List{listTitle: jsonList.Title, complete: jsonList.Complete, extraLines: jsonList.Notes}
+			for _, jsonTask := range jsonList.Tasks {
+				task, err := jsonTask.listItem()
+				if err != nil {
+					return fmt.Errorf("Error in file %v, task %q: %v", d.fileName, jsonTask.Name, err)
+				}
+				list.listItems = append(list.listItems, task)
+			}
+			board.lists = append(board.lists, list)
+		}
+		d.boards = append(d.boards, board)
+	}
+	d.assignTaskIDs()
+	return nil
+}
+
+func (t jsonTask) listItem() (ListItem, error) {
+	task := ListItem{
+		ID:              t.ID,
+		ItemName:        t.Name,
+		ItemDescription: t.Description,
+		Tags:            t.Tags,
+		Done:            t.Done,
+	}
+	for _, note := range t.Notes {
+		task.body = append(task.body, bodyLine{kind: extraLine, text: note})
+	}
+	if len(t.Priority) > 0 {
+		priority, ok := ParsePriority(t.Priority)
+		if !ok {
+			return ListItem{}, fmt.Errorf("unknown priority %q", t.Priority)
+		}
+		task.Priority = priority
+	}
+	if len(t.Due) > 0 {
+		dueDate, err := time.Parse(DueDateLayout, t.Due)
+		if err != nil {
+			return ListItem{}, fmt.Errorf("due date must be written as %v, got %q", DueDateLayout, t.Due)
+		}
+		task.DueDate = dueDate
+	}
+	if len(t.DoneDate) > 0 {
+		doneDate, err := time.Parse(DueDateLayout, t.DoneDate)
+		if err != nil {
+			return ListItem{}, fmt.Errorf("done date must be written as %v, got %q", DueDateLayout, t.DoneDate)
+		}
+		task.DoneDate = doneDate
+	}
+	for _, subtask := range t.Subtasks {
+		task.Subtasks = append(task.Subtasks, Subtask{Name: subtask.Name, Done: subtask.Done})
+	}
+	return task, nil
+}
+
+// returns the boards of the data in the form of the JSON file
+func (d *Data) toJSON() jsonFile {
+	file := jsonFile{FrontMatter: d.frontMatter, Notes: d.preamble, Boards: []jsonBoard{}}
+	for _, board := range d.boards {
+		jsonBoard := jsonBoard{Name: board.boardName, Notes: board.extraLines, Lists: []jsonList{}}
+		for _, list := range board.lists {
+			jsonList := jsonList{Title: list.listTitle, Complete: list.complete, Notes: list.extraLines, Tasks: []jsonTask{}}
+			for _, task := range list.listItems {
+				jsonTask := jsonTask{
+					ID:          task.ID,
+					Name:        task.ItemName,
+					Description: task.ItemDescription,
+					Priority:    task.Priority.String(),
+					Tags:        task.Tags,
+					Done:        task.Done,
+					Notes:       task.notes(),
+				}
+				if !task.DueDate.IsZero() {
+					jsonTask.Due = task.DueDate.Format(DueDateLayout)
+				}
+				if !task.DoneDate.IsZero() {
+					jsonTask.DoneDate = task.DoneDate.Format(DueDateLayout)
+				}
+				for _, subtask := range task.Subtasks {
+					jsonTask.Subtasks = append(jsonTask.Subtasks, jsonSubtask{Name: subtask.Name, Done: subtask.Done})
+				}
+				jsonList.Tasks = append(jsonList.Tasks, jsonTask)
+			}
+			jsonBoard.Lists = append(jsonBoard.Lists, jsonList)
+		}
+		file.Boards = append(file.Boards, jsonBoard)
+	}
+	return file
+}
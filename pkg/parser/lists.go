@@ -0,0 +1,212 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// adds an empty list to the active board at a position
+func (d *Data) AddList(listIdx int, listTitle string) error {
+	if err := d.CheckListTitle(listTitle, -1); err != nil {
+		return err
+	}
+	if err := checkBounds(listIdx, d.GetListCount()+1); err != nil {
+		return err
+	}
+	return d.InsertList(listIdx, List{listTitle: listTitle})
+}
+
+// puts a list, with its tasks, on the active board at a position
+func (d *Data) InsertList(listIdx int, list List) error {
+	board := d.board()
+	if err := checkBounds(listIdx, len(board.lists)+1); err != nil {
+		return err
+	}
+	board.lists = append(board.lists, List{})
+	copy(board.lists[listIdx+1:], board.lists[listIdx:])
+	board.lists[listIdx] = list
+	return nil
+}
+
+// renames a list of the active board. the wip limit and the done list of the
+// front matter refer to lists by title, they are renamed with it.
+func (d *Data) RenameList(listIdx int, listTitle string) error {
+	list, err := d.GetList(listIdx)
+	if err != nil {
+		return err
+	}
+	if err := d.CheckListTitle(listTitle, listIdx); err != nil {
+		return err
+	}
+	d.renameFrontMatterList(list, listTitle)
+	list.listTitle = listTitle
+	return nil
+}
+
+// undoes RenameList: gives a list its title back and the front matter the
+// lines it had before, as returned by FrontMatter. nil leaves the front matter.
+func (d *Data) RestoreListTitle(listIdx int, listTitle string, frontMatter []string) error {
+	list, err := d.GetList(listIdx)
+	if err != nil {
+		return err
+	}
+	if err := d.CheckListTitle(listTitle, listIdx); err != nil {
+		return err
+	}
+	list.listTitle = listTitle
+	if frontMatter != nil {
+		d.setFrontMatter(frontMatter)
+	}
+	return nil
+}
+
+// removes a list of the active board and returns it as it was. its tasks
+// are moved to the end of the list at destListIdx, or removed with it if
+// destListIdx is -1, tasks moved out of the done list are marked as not
+// done. the last list of a board cannot be removed. the wip limit and the
+// done list of the front matter that refer to it are removed.
+func (d *Data) RemoveList(listIdx, destListIdx int) (List, error) {
+	list, err := d.GetList(listIdx)
+	if err != nil {
+		return List{}, err
+	}
+	if d.GetListCount() == 1 {
+		return List{}, fmt.Errorf("Cannot delete list %q: it is the last list of the board", list.listTitle)
+	}
+	if destListIdx == listIdx {
+		return List{}, fmt.Errorf("Cannot move the tasks of list %q to itself", list.listTitle)
+	}
+	removedList := *list
+	wasDoneList := listIdx == d.GetDoneListIdx()
+	if destListIdx >= 0 {
+		destList, err := d.GetList(destListIdx)
+		if err != nil {
+			return List{}, err
+		}
+		destList.listItems = append(destList.listItems, list.listItems...)
+	}
+	d.renameFrontMatterList(list, "")
+	board := d.board()
+	board.lists = append(board.lists[:listIdx], board.lists[listIdx+1:]...)
+	if destListIdx > listIdx {
+		// the lists after the removed one moved one to the left
+		destListIdx--
+	}
+	if wasDoneList && destListIdx >= 0 && destListIdx != d.GetDoneListIdx() {
+		destList := &board.lists[destListIdx]
+		clearDone(destList.listItems[len(destList.listItems)-len(removedList.listItems):])
+	}
+	return removedList, nil
+}
+
+// marks the tasks as not done, returns the ones that were done as they were
+func clearDone(listItems []ListItem) []ListItem {
+	var cleared []ListItem
+	for idx := range listItems {
+		if listItems[idx].Done {
+			cleared = append(cleared, listItems[idx])
+			listItems[idx].setDone(false)
+		}
+	}
+	return cleared
+}
+
+// undoes RemoveList: takes the tasks of the list back from the list they
+// were moved to, destListIdx as it was before the list was removed, puts
+// the list back at its position and gives the front matter the lines it had
+// before, as returned by FrontMatter. nil leaves the front matter.
+func (d *Data) RestoreList(listIdx int, list List, destListIdx int, frontMatter []string) error {
+	if destListIdx >= 0 {
+		if destListIdx > listIdx {
+			// the lists after the removed one moved one to the left
+			destListIdx--
+		}
+		destList, err := d.GetList(destListIdx)
+		if err != nil {
+			return err
+		}
+		taskCount := len(destList.listItems) - len(list.listItems)
+		if taskCount < 0 {
+			return fmt.Errorf("Cannot restore list %q: the tasks of it are not in list %q anymore", list.listTitle, destList.listTitle)
+		}
+		destList.listItems = destList.listItems[:taskCount]
+	}
+	if frontMatter != nil {
+		d.setFrontMatter(frontMatter)
+	}
+	return d.InsertList(listIdx, list)
+}
+
+// moves a list of the active board to another position. the tasks of the
+// done list are marked as not done if it is not the done list anymore, e.g.
+// the last list when the front matter names none. they are returned as they
+// were, see RestoreTaskDone.
+func (d *Data) MoveList(listIdx, newListIdx int) ([]ListItem, error) {
+	board := d.board()
+	if err := checkBounds(listIdx, len(board.lists)); err != nil {
+		return nil, err
+	}
+	if err := checkBounds(newListIdx, len(board.lists)); err != nil {
+		return nil, err
+	}
+	doneListTitle := board.lists[d.GetDoneListIdx()].listTitle
+	list := board.lists[listIdx]
+	board.lists = append(board.lists[:listIdx], board.lists[listIdx+1:]...)
+	board.lists = append(board.lists, List{})
+	copy(board.lists[newListIdx+1:], board.lists[newListIdx:])
+	board.lists[newListIdx] = list
+	for listIdx := range board.lists {
+		if board.lists[listIdx].listTitle == doneListTitle && listIdx != d.GetDoneListIdx() {
+			return clearDone(board.lists[listIdx].listItems), nil
+		}
+	}
+	return nil, nil
+}
+
+// returns an error if the title cannot be the title of a list of the active
+// board: it is empty, takes more than one line or another list has it
+func (d *Data) CheckListTitle(listTitle string, listIdx int) error {
+	if len(strings.TrimSpace(listTitle)) == 0 {
+		return fmt.Errorf("Cannot name a list %q: the name is empty", listTitle)
+	}
+	if strings.ContainsAny(listTitle, "\r\n") {
+		return fmt.Errorf("Cannot name a list %q: the name takes more than one line", listTitle)
+	}
+	for idx, title := range d.GetListNames() {
+		if idx != listIdx && strings.EqualFold(title, listTitle) {
+			return fmt.Errorf("Cannot name a list %q: the board has a list of that name", listTitle)
+		}
+	}
+	return nil
+}
+
+// returns the title of the list
+func (l List) Title() string {
+	return l.listTitle
+}
+
+// returns the number of tasks of the list
+func (l List) TaskCount() int {
+	return len(l.listItems)
+}
+
+// returns the lines of the list as they are written in a seiban file, see ParseList
+func (l List) Lines() []string {
+	lines := append([]string{"## " + l.listTitle}, l.extraLines...)
+	for _, listItem := range l.listItems {
+		lines = append(lines, listItem.Lines()...)
+	}
+	return lines
+}
+
+// parses the lines of one list, as returned by List.Lines
+func ParseList(lines []string) (List, error) {
+	d := &Data{}
+	if err := d.ParseData(append([]string{"# list"}, lines...)); err != nil {
+		return List{}, err
+	}
+	if len(d.boards) != 1 || len(d.board().lists) != 1 {
+		return List{}, fmt.Errorf("Cannot parse list: %q is not one list", lines)
+	}
+	return d.board().lists[0], nil
+}
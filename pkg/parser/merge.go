@@ -0,0 +1,434 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+)
+
+// the board and list a task is in
+type taskLocation struct {
+	boardName string
+	listTitle string
+}
+
+// returns the location as "board › list"
+func (l taskLocation) String() string {
+	return l.boardName + " › " + l.listTitle
+}
+
+// a task as it is in one version of the file
+type taskVersion struct {
+	item     ListItem
+	location taskLocation
+	// position of the task, used when its list is not in the merged file
+	boardIdx, listIdx, taskIdx int
+}
+
+// a task that was changed both in memory and in the file on disk, in different ways.
+// the merge keeps the version in memory until the conflict is resolved.
+//
+// a change to the boards or lists that cannot be merged, e.g. a list renamed
+// differently on both sides, has no ID but a Structure. the merge keeps the
+// change made in memory for it, but keeps a board or list that was removed on
+// one side and changed on the other. it cannot be resolved by ResolveConflict.
+type MergeConflict struct {
+	ID string
+	// what a change to the boards or lists was made to, e.g. `list "DONE"`,
+	// and what was done to it in memory and in the file on disk
+	Structure                string
+	OursChange, TheirsChange string
+	// the task in memory and in the file on disk, nil where it was removed
+	Ours, Theirs *ListItem
+	// the lists the task is in, as "board › list", empty where it was removed
+	OursList, TheirsList string
+	// where the task is on disk, for ResolveConflict
+	theirs taskVersion
+}
+
+// merges the changes made to the file on disk since it was last read or
+// written with the changes made in memory, task by task: additions, removals,
+// moves between and within lists and edits. the boards and lists are merged
+// the same way, see mergeStructure. the merged data is not saved, tasks changed on both sides are returned as
+// conflicts, see ResolveConflict.
+func (d *Data) MergeFile() ([]MergeConflict, error) {
+	// the boards as they were last loaded or saved
+	base := &Data{fileName: d.fileName}
+	if err := base.ParseData(d.savedContent); err != nil {
+		return nil, err
+	}
+	theirs, err := d.loadStored()
+	if err != nil {
+		return nil, err
+	}
+	theirs.adoptTaskIDs(base)
+	merged, conflicts := mergeData(base, d, theirs)
+	merged.fileName = d.fileName
+	merged.readOnly = d.readOnly
+	merged.storage = d.storage
+	// the stored boards are now the ones the data started from, the merged changes are not saved yet
+	merged.savedContent = theirs.Content()
+	merged.storedHash = theirs.storedHash
+	merged.selectBoard(d.GetBoardName())
+	*d = *merged
+	return conflicts, nil
+}
+
+// replaces the version of the task in memory by the one in the file on disk,
+// unless keepOurs is set. the data is not saved.
+func (d *Data) ResolveConflict(conflict MergeConflict, keepOurs bool) error {
+	if keepOurs || len(conflict.Structure) > 0 {
+		return nil
+	}
+	if boardIdx, listIdx, taskIdx, err := d.findTaskInFile(conflict.ID); err == nil {
+		list := &d.boards[boardIdx].lists[listIdx]
+		list.listItems = slices.Delete(slices.Clone(list.listItems), taskIdx, taskIdx+1)
+	}
+	if conflict.Theirs == nil {
+		return nil
+	}
+	list := d.listOf(conflict.theirs)
+	taskIdx := min(conflict.theirs.taskIdx, len(list.listItems))
+	list.listItems = slices.Insert(slices.Clone(list.listItems), taskIdx, *conflict.Theirs)
+	return nil
+}
+
+// merges the files ours and theirs, both changed from base, into ours and
+// saves it, for the merge driver of git. tasks that have no id are matched by
+// name or position, see adoptTaskIDs. conflicts are written to the file
+// between git style conflict markers around the two versions of the task,
+// the version of ours first. changes to the boards and lists that cannot be
+// merged are only returned.
+func MergeFiles(base, ours, theirs *Data) ([]MergeConflict, error) {
+	ours.adoptTaskIDs(base)
+	theirs.adoptTaskIDs(base)
+	merged, conflicts := mergeData(base, ours, theirs)
+	merged.fileName = ours.fileName
+	merged.storage = ours.storage
+	merged.conflicts = make(map[string]MergeConflict)
+	for _, conflict := range conflicts {
+		if len(conflict.Structure) > 0 {
+			continue
+		}
+		if conflict.Ours == nil {
+			// the marked task goes where theirs is
+			merged.ResolveConflict(conflict, false)
+		}
+		merged.conflicts[conflict.ID] = conflict
+	}
+	// not backed up, the files git merges are temporary files
+	if _, err := merged.storage.Save(merged); err != nil {
+		return nil, fmt.Errorf("Cannot save file %v: %v", merged.fileName, err)
+	}
+	return conflicts, nil
+}
+
+// returns the lines of a task, written by lines. a task with a conflict is
+// written as both of its versions between conflict markers.
+func (d *Data) withConflictMarkers(item ListItem, lines func(ListItem) []string) []string {
+	conflict, ok := d.conflicts[item.ID]
+	if !ok {
+		return lines(item)
+	}
+	label := func(list string) string {
+		if len(list) == 0 {
+			return " (removed)"
+		}
+		return " (" + list + ")"
+	}
+	marked := []string{"<<<<<<< ours" + label(conflict.OursList)}
+	if conflict.Ours != nil {
+		marked = append(marked, lines(*conflict.Ours)...)
+	}
+	marked = append(marked, "=======")
+	if conflict.Theirs != nil {
+		marked = append(marked, lines(*conflict.Theirs)...)
+	}
+	return append(marked, ">>>>>>> theirs"+label(conflict.TheirsList))
+}
+
+// merges the boards, lists and tasks of ours and theirs, base is the version both started from
+func mergeData(base, ours, theirs *Data) (*Data, []MergeConflict) {
+	merged, locations, conflicts := mergeStructure(base, ours, theirs)
+	baseTasks, baseOrder, baseIDs := base.taskVersions(locations[baseVersion])
+	ourTasks, ourOrder, ourIDs := ours.taskVersions(locations[ourVersion])
+	theirTasks, theirOrder, theirIDs := theirs.taskVersions(locations[theirVersion])
+
+	// the version of every task that is in the merged file
+	resolved := make(map[string]taskVersion)
+	addConflict := func(id string, o, t *taskVersion) {
+		conflict := MergeConflict{ID: id}
+		if o != nil {
+			conflict.Ours = &o.item
+			conflict.OursList = o.location.String()
+			resolved[id] = *o
+		}
+		if t != nil {
+			conflict.Theirs = &t.item
+			conflict.TheirsList = t.location.String()
+			conflict.theirs = *t
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	var ids []string
+	for _, id := range slices.Concat(ourIDs, theirIDs, baseIDs) {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		b, inBase := baseTasks[id]
+		o, inOurs := ourTasks[id]
+		t, inTheirs := theirTasks[id]
+		switch {
+		case !inBase && inOurs && inTheirs && !sameTask(o.item, t.item):
+			addConflict(id, &o, &t)
+		case !inBase && inOurs:
+			resolved[id] = o
+		case !inBase:
+			resolved[id] = t
+		case !inOurs && !inTheirs:
+			// removed on both sides
+		case !inOurs:
+			if !sameTask(b.item, t.item) {
+				addConflict(id, nil, &t)
+			}
+		case !inTheirs:
+			if !sameTask(b.item, o.item) {
+				addConflict(id, &o, nil)
+			}
+		default:
+			useOurItem, itemConflict := pickChange(!sameTask(b.item, o.item), !sameTask(b.item, t.item), sameTask(o.item, t.item))
+			useOurLocation, locationConflict := pickChange(o.location != b.location, t.location != b.location, o.location == t.location)
+			if itemConflict || locationConflict {
+				addConflict(id, &o, &t)
+				continue
+			}
+			version := t
+			if useOurLocation {
+				version = o
+			}
+			version.item = t.item
+			if useOurItem {
+				version.item = o.item
+			}
+			resolved[id] = version
+		}
+	}
+
+	placed := make(map[string]bool)
+	for boardIdx := range merged.boards {
+		board := &merged.boards[boardIdx]
+		for listIdx := range board.lists {
+			list := &board.lists[listIdx]
+			location := taskLocation{board.boardName, list.listTitle}
+			primary, secondary, _ := mergeOrder(baseOrder[location], ourOrder[location], theirOrder[location])
+			// tasks the other side added or moved here go after the task before them on that side
+			listIDs := interleave(primary, secondary, func(id string) bool {
+				version, ok := resolved[id]
+				return ok && version.location == location && !placed[id]
+			})
+			for _, id := range listIDs {
+				list.listItems = append(list.listItems, resolved[id].item)
+				placed[id] = true
+			}
+		}
+	}
+	// tasks whose list is not in the merged file are put in another list and reported
+	for _, id := range ids {
+		version, ok := resolved[id]
+		if !ok || placed[id] {
+			continue
+		}
+		list := merged.listOf(version)
+		list.listItems = append(list.listItems, version.item)
+		if slices.ContainsFunc(conflicts, func(conflict MergeConflict) bool { return conflict.ID == id }) {
+			continue
+		}
+		o, inOurs := ourTasks[id]
+		t, inTheirs := theirTasks[id]
+		conflict := MergeConflict{ID: id}
+		if inOurs {
+			conflict.Ours = &o.item
+			conflict.OursList = o.location.String()
+		}
+		if inTheirs {
+			conflict.Theirs = &t.item
+			conflict.TheirsList = t.location.String()
+			conflict.theirs = t
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return merged, conflicts
+}
+
+// decides which side's change to a value to keep, reports a conflict when both changed it differently
+func pickChange(oursChanged, theirsChanged, sameChange bool) (useOurs, conflict bool) {
+	switch {
+	case !oursChanged:
+		return false, false
+	case !theirsChanged || sameChange:
+		return true, false
+	}
+	return false, true
+}
+
+// returns the order of the tasks of a list, or of the lists of a board, to
+// follow and the order of the other side: ours if only ours reordered the
+// ones that are on all sides, else theirs. reports a conflict when both
+// reordered them differently.
+func mergeOrder[T comparable](baseIDs, ourIDs, theirIDs []T) (primary, secondary []T, conflict bool) {
+	inAll := func(id T) bool {
+		return slices.Contains(baseIDs, id) && slices.Contains(ourIDs, id) && slices.Contains(theirIDs, id)
+	}
+	common := func(ids []T) []T {
+		var commonIDs []T
+		for _, id := range ids {
+			if inAll(id) {
+				commonIDs = append(commonIDs, id)
+			}
+		}
+		return commonIDs
+	}
+	oursReordered := !slices.Equal(common(ourIDs), common(baseIDs))
+	theirsReordered := !slices.Equal(common(theirIDs), common(baseIDs))
+	if oursReordered && !theirsReordered {
+		return ourIDs, theirIDs, false
+	}
+	return theirIDs, ourIDs, oursReordered && !slices.Equal(common(ourIDs), common(theirIDs))
+}
+
+// returns the ids of primary, then puts the ones of secondary that are not in
+// it after the id before them in secondary. ids include rejects are left out.
+func interleave[T comparable](primary, secondary []T, include func(T) bool) []T {
+	var ids []T
+	for _, id := range primary {
+		if include(id) && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	for idx, id := range secondary {
+		if !include(id) || slices.Contains(ids, id) {
+			continue
+		}
+		pos := 0
+		for prev := idx - 1; prev >= 0; prev-- {
+			if prevPos := slices.Index(ids, secondary[prev]); prevPos >= 0 {
+				pos = prevPos + 1
+				break
+			}
+		}
+		ids = slices.Insert(ids, pos, id)
+	}
+	return ids
+}
+
+// reports whether two versions of a task are written the same way
+func sameTask(a, b ListItem) bool {
+	return slices.Equal(a.lines(true), b.lines(true))
+}
+
+// returns the tasks of all the boards by id, the ids of the tasks of every
+// list in order and the ids of all the tasks in order. the location of the
+// tasks of a list is taken from locations by board and list index, if given.
+func (d *Data) taskVersions(locations [][]taskLocation) (map[string]taskVersion, map[taskLocation][]string, []string) {
+	versions := make(map[string]taskVersion)
+	order := make(map[taskLocation][]string)
+	var ids []string
+	for boardIdx, board := range d.boards {
+		for listIdx, list := range board.lists {
+			location := taskLocation{board.boardName, list.listTitle}
+			if locations != nil {
+				location = locations[boardIdx][listIdx]
+			}
+			for taskIdx, item := range list.listItems {
+				versions[item.ID] = taskVersion{item, location, boardIdx, listIdx, taskIdx}
+				order[location] = append(order[location], item.ID)
+				ids = append(ids, item.ID)
+			}
+		}
+	}
+	return versions, order, ids
+}
+
+// where a task is in its file
+type taskPosition struct {
+	location taskLocation
+	taskIdx  int
+}
+
+// gives the tasks that have no id in the file, and so got a new one when the
+// file was parsed, the id of the task of the same name in base. a task whose
+// name changed gets the id of the task of base without an id in the file at
+// the same position, so that an edit is merged as an edit and not as a task
+// removed and another one added.
+func (d *Data) adoptTaskIDs(base *Data) {
+	baseTasks, _, baseIDs := base.taskVersions(nil)
+	tasks, _, _ := d.taskVersions(nil)
+	// ids of the tasks of base that are not in d, by name
+	unmatched := make(map[string][]string)
+	for _, id := range baseIDs {
+		if _, ok := tasks[id]; !ok {
+			name := baseTasks[id].item.ItemName
+			unmatched[name] = append(unmatched[name], id)
+		}
+	}
+	adopted := make(map[string]bool)
+	var unnamed []*ListItem
+	for boardIdx := range d.boards {
+		for listIdx := range d.boards[boardIdx].lists {
+			listItems := d.boards[boardIdx].lists[listIdx].listItems
+			for taskIdx := range listItems {
+				item := &listItems[taskIdx]
+				if _, ok := baseTasks[item.ID]; ok {
+					continue
+				}
+				if ids := unmatched[item.ItemName]; len(ids) > 0 {
+					item.ID = ids[0]
+					unmatched[item.ItemName] = ids[1:]
+					adopted[item.ID] = true
+				} else if d.assignedIDs[item.ID] {
+					unnamed = append(unnamed, item)
+				}
+			}
+		}
+	}
+	positions := make(map[taskPosition]string)
+	for _, id := range baseIDs {
+		if _, ok := tasks[id]; !ok && !adopted[id] && base.assignedIDs[id] {
+			positions[taskPosition{baseTasks[id].location, baseTasks[id].taskIdx}] = id
+		}
+	}
+	for _, item := range unnamed {
+		version := tasks[item.ID]
+		if id, ok := positions[taskPosition{version.location, version.taskIdx}]; ok {
+			item.ID = id
+		}
+	}
+}
+
+// returns the list of the data a task version belongs in: the list of the same
+// board and title, else the list at the same position, else the first list
+func (d *Data) listOf(version taskVersion) *List {
+	for boardIdx := range d.boards {
+		board := &d.boards[boardIdx]
+		for listIdx := range board.lists {
+			if (taskLocation{board.boardName, board.lists[listIdx].listTitle}) == version.location {
+				return &board.lists[listIdx]
+			}
+		}
+	}
+	for boardIdx := range d.boards {
+		board := &d.boards[boardIdx]
+		if board.boardName == version.location.boardName && version.listIdx < len(board.lists) {
+			return &board.lists[version.listIdx]
+		}
+	}
+	for boardIdx := range d.boards {
+		if len(d.boards[boardIdx].lists) > 0 {
+			return &d.boards[boardIdx].lists[0]
+		}
+	}
+	board := d.board()
+	board.lists = append(board.lists, List{listTitle: version.location.listTitle})
+	return &board.lists[len(board.lists)-1]
+}
@@ -0,0 +1,249 @@
+package parser
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+const mergeBase = "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n"
+
+// loads mergeBase from a file, makes the changes in memory without saving
+// them and writes theirs to the file as another program would
+func mergeSetup(t *testing.T, change func(d *Data), theirs string) *Data {
+	t.Helper()
+	d, _ := saveText(t, mergeBase)
+	change(d)
+	if err := os.WriteFile("seiban.md", []byte(theirs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestMergeFile(t *testing.T) {
+	d := mergeSetup(t, func(d *Data) {
+		task, _ := d.GetTask(0, 0)
+		task.Done = true
+	}, "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two edited <!-- id:b2 -->\n\t- three <!-- id:c3 -->\n\n\n## DOING\n\n\n## DONE\n\n")
+	conflicts, err := d.MergeFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+	want := "# b\n\n## TODO\n\t- [x] one <!-- id:a1 -->\n\t- [ ] two edited <!-- id:b2 -->\n\t- [ ] three <!-- id:c3 -->\n\n\n## DOING\n\n\n## DONE\n\n"
+	if got := strings.Join(d.Content(), "\n"); got != want {
+		t.Errorf("merged data:\n%q\nwant:\n%q", got, want)
+	}
+	if !d.HasUnsavedChanges() {
+		t.Error("the merged changes are not unsaved changes")
+	}
+	if changed, err := d.FileChanged(); err != nil || changed {
+		t.Errorf("FileChanged() = %v, %v after the file was merged", changed, err)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	for _, keepOurs := range []bool{true, false} {
+		d := mergeSetup(t, func(d *Data) {
+			task, _ := d.GetTaskByID("a1")
+			task.ItemName = "one ours"
+		}, "# b\n\n## TODO\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\t- one theirs <!-- id:a1 -->\n\n\n## DONE\n\n")
+		conflicts, err := d.MergeFile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conflicts) != 1 || conflicts[0].ID != "a1" || conflicts[0].Ours.ItemName != "one ours" || conflicts[0].Theirs.ItemName != "one theirs" {
+			t.Fatalf("conflicts = %+v, want one for a1", conflicts)
+		}
+		if err := d.ResolveConflict(conflicts[0], keepOurs); err != nil {
+			t.Fatal(err)
+		}
+		want, wantList := "one ours", 0
+		if !keepOurs {
+			want, wantList = "one theirs", 1
+		}
+		listIdx, _, _ := d.FindTask("a1")
+		if task, _ := d.GetTaskByID("a1"); task.ItemName != want || listIdx != wantList {
+			t.Errorf("keeping ours %v: task is %q in list %v", keepOurs, task.ItemName, listIdx)
+		}
+	}
+}
+
+func TestSaveMerged(t *testing.T) {
+	theirs := "# b\n\n## TODO\n\t- one theirs <!-- id:a1 -->\n\t- two edited <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n"
+	d := mergeSetup(t, func(d *Data) {
+		if err := d.AddNewTask(1, ListItem{ID: "c3", ItemName: "three"}, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.EditTaskByID("a1", ListItem{ItemName: "one ours"}); err != nil {
+			t.Fatal(err)
+		}
+	}, theirs)
+	conflicts, err := d.SaveMerged()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].ID != "a1" {
+		t.Fatalf("conflicts = %+v, want one for a1", conflicts)
+	}
+	if saved, _ := os.ReadFile("seiban.md"); string(saved) != theirs {
+		t.Errorf("saved with a conflict left:\n%s", saved)
+	}
+	if err := d.ResolveConflict(conflicts[0], false); err != nil {
+		t.Fatal(err)
+	}
+	if conflicts, err := d.SaveMerged(); err != nil || len(conflicts) > 0 {
+		t.Fatalf("SaveMerged() = %+v, %v once the conflict is resolved", conflicts, err)
+	}
+	want := "# b\n\n## TODO\n\t- one theirs <!-- id:a1 -->\n\t- two edited <!-- id:b2 -->\n\n\n## DOING\n\t- three <!-- id:c3 -->\n\n\n## DONE\n\n\n"
+	if saved, _ := os.ReadFile("seiban.md"); string(saved) != want {
+		t.Errorf("saved as:\n%q\nwant:\n%q", saved, want)
+	}
+	if changed, err := d.FileChanged(); err != nil || changed || d.HasUnsavedChanges() {
+		t.Errorf("FileChanged() = %v, %v and HasUnsavedChanges() = %v after SaveMerged", changed, err, d.HasUnsavedChanges())
+	}
+}
+
+// parses the text of a board file
+func parseText(t *testing.T, text string) *Data {
+	t.Helper()
+	d := &Data{}
+	if err := d.ParseData(strings.Split(text, "\n")); err != nil {
+		t.Fatalf("ParseData: %v", err)
+	}
+	return d
+}
+
+var newIDs = regexp.MustCompile(`<!-- id:[0-9a-f]+ -->`)
+
+type mergeTest struct {
+	name, base, ours, theirs, want string
+	// the ids of the conflicting tasks, or the structure of the structural conflicts
+	conflicts []string
+}
+
+func runMergeTests(t *testing.T, tests []mergeTest) {
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base := test.base
+			if len(base) == 0 {
+				base = mergeBase
+			}
+			ours, _ := saveText(t, test.ours)
+			conflicts, err := MergeFiles(parseText(t, base), ours, parseText(t, test.theirs))
+			if err != nil {
+				t.Fatalf("MergeFiles: %v", err)
+			}
+			got := strings.Join(files.OpenFile("seiban.md"), "\n")
+			if !strings.Contains(base, "id:") {
+				// the ids given to the tasks are random
+				got = newIDs.ReplaceAllString(got, "<!-- id:ID -->")
+			}
+			if got != test.want {
+				t.Errorf("merged file:\n%q\nwant:\n%q", got, test.want)
+			}
+			var conflictIDs []string
+			for _, conflict := range conflicts {
+				if len(conflict.Structure) > 0 {
+					conflictIDs = append(conflictIDs, conflict.Structure)
+				} else {
+					conflictIDs = append(conflictIDs, conflict.ID)
+				}
+			}
+			if strings.Join(conflictIDs, ", ") != strings.Join(test.conflicts, ", ") {
+				t.Errorf("conflicts = %q, want %q", conflictIDs, test.conflicts)
+			}
+		})
+	}
+}
+
+func TestMergeFiles(t *testing.T) {
+	runMergeTests(t, []mergeTest{
+		{
+			name:   "tasks added on both sides",
+			ours:   "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\t- ours <!-- id:c3 -->\n\n\n## DOING\n\n\n## DONE\n\n",
+			theirs: "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\t- theirs <!-- id:d4 -->\n\n\n## DONE\n\n",
+			want:   "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\t- ours <!-- id:c3 -->\n\n\n## DOING\n\t- theirs <!-- id:d4 -->\n\n\n## DONE\n\n",
+		},
+		{
+			name:   "task moved on one side and edited on the other",
+			ours:   "# b\n\n## TODO\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\t- one <!-- id:a1 -->\n\n\n## DONE\n\n",
+			theirs: "# b\n\n## TODO\n\t- one renamed <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n",
+			want:   "# b\n\n## TODO\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\t- one renamed <!-- id:a1 -->\n\n\n## DONE\n\n",
+		},
+		{
+			name:      "task edited on both sides",
+			ours:      "# b\n\n## TODO\n\t- one ours <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n",
+			theirs:    "# b\n\n## TODO\n\t- one theirs <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n",
+			want:      "# b\n\n## TODO\n<<<<<<< ours (b › TODO)\n\t- one ours <!-- id:a1 -->\n=======\n\t- one theirs <!-- id:a1 -->\n>>>>>>> theirs (b › TODO)\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n",
+			conflicts: []string{"a1"},
+		},
+		{
+			name:   "tasks without ids",
+			base:   "# b\n\n## TODO\n\t- one\n\t- two\n\n\n## DONE\n\n",
+			ours:   "# b\n\n## TODO\n\t- two\n\n\n## DONE\n\t- one\n\n",
+			theirs: "# b\n\n## TODO\n\t- one\n\t- two\n\t- three\n\n\n## DONE\n\n",
+			want:   "# b\n\n## TODO\n\t- two <!-- id:ID -->\n\t- three <!-- id:ID -->\n\n\n## DONE\n\t- one <!-- id:ID -->\n\n",
+		},
+		{
+			name:   "task without an id edited on one side and moved on the other",
+			base:   "# b\n\n## TODO\n\t- one\n\t- two\n\n\n## DONE\n\n",
+			ours:   "# b\n\n## TODO\n\t- one edited\n\t- two\n\n\n## DONE\n\n",
+			theirs: "# b\n\n## TODO\n\t- two\n\n\n## DONE\n\t- one\n\n",
+			want:   "# b\n\n## TODO\n\t- two <!-- id:ID -->\n\n\n## DONE\n\t- one edited <!-- id:ID -->\n\n",
+		},
+		{
+			name:   "list added on one side and another renamed on the other",
+			ours:   "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## DONE\n\n\n## REVIEW\n\t- r <!-- id:c3 -->\n\n",
+			theirs: "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## FINISHED\n\n",
+			want:   "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## FINISHED\n\n\n## REVIEW\n\t- r <!-- id:c3 -->\n\n",
+		},
+		{
+			name:   "lists added on both sides",
+			ours:   mergeBase + "\n## OURS\n\n",
+			theirs: mergeBase + "\n## THEIRS\n\n",
+			want:   mergeBase + "\n## OURS\n\n\n## THEIRS\n\n",
+		},
+		{
+			name:      "list renamed on both sides",
+			ours:      "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## OURS\n\n",
+			theirs:    "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## THEIRS\n\n",
+			want:      "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\n\n## OURS\n\n",
+			conflicts: []string{`list "DONE" of board "b"`},
+		},
+		{
+			name:      "list removed on one side and changed on the other",
+			ours:      "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DONE\n\n",
+			theirs:    "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\t- three <!-- id:c3 -->\n\n\n## DONE\n\n",
+			want:      "# b\n\n## TODO\n\t- one <!-- id:a1 -->\n\t- two <!-- id:b2 -->\n\n\n## DOING\n\t- three <!-- id:c3 -->\n\n\n## DONE\n\n",
+			conflicts: []string{`list "DOING" of board "b"`},
+		},
+	})
+}
+
+func TestMergeFilesWithoutBackup(t *testing.T) {
+	t.Chdir(t.TempDir())
+	var versions []*Data
+	for _, fileName := range []string{"base", "ours", "theirs"} {
+		if err := os.WriteFile(fileName, []byte(mergeBase), 0644); err != nil {
+			t.Fatal(err)
+		}
+		d := &Data{}
+		d.SetFileName(fileName)
+		if err := d.Load(); err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, d)
+	}
+	if _, err := MergeFiles(versions[0], versions[1], versions[2]); err != nil {
+		t.Fatal(err)
+	}
+	if backups, err := files.ListBackups("ours"); err != nil || len(backups) > 0 {
+		t.Errorf("ListBackups() = %v, %v after the merge", backups, err)
+	}
+}
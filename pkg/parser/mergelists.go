@@ -0,0 +1,323 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// the boards and lists are merged like the tasks, before them. a board or
+// list of ours or theirs is the version of the one of base with the same
+// title, else of the one it shares the most tasks with (it was renamed),
+// else of the one at the same place between the same neighbours. additions,
+// removals, renames, changed notes and the order are then merged three-way.
+
+// the versions of a merge, as indices of the arrays below
+const (
+	baseVersion = iota
+	ourVersion
+	theirVersion
+)
+
+// one version of a board or a list, as the merge of the boards and lists sees it
+type part struct {
+	title string
+	// all of it but the title and the tasks, e.g. the notes under the title
+	body string
+	// ids of its tasks in order, to find it when it was renamed
+	taskIDs []string
+	// all of it, to tell whether it changed at all
+	content string
+}
+
+// a board or list of the merged file
+type mergedPart struct {
+	// index of it in every version, -1 where it is not
+	idx   [3]int
+	title string
+	// the version its body is taken from
+	from int
+}
+
+// merges the boards and lists of ours and theirs, base is the version both
+// started from. returns the data with the merged boards and empty lists, the
+// location in the merged data of every list of every version by board and
+// list index, and the changes that cannot be merged. those keep the change of
+// ours, but a board or list removed on one side and changed on the other is kept.
+func mergeStructure(base, ours, theirs *Data) (*Data, [3][][]taskLocation, []MergeConflict) {
+	versions := [3]*Data{base, ours, theirs}
+	var conflicts []MergeConflict
+	merged := *theirs
+	merged.conflicts = nil
+	merged.boardIdx = 0
+	if mergeLines("the front matter", base.frontMatter, ours.frontMatter, theirs.frontMatter, &conflicts) {
+		merged.frontMatter, merged.config = ours.frontMatter, ours.config
+	}
+	if mergeLines("the lines before the first board", base.preamble, ours.preamble, theirs.preamble, &conflicts) {
+		merged.preamble = ours.preamble
+	}
+	if mergeLines("the Obsidian settings", base.settings, ours.settings, theirs.settings, &conflicts) {
+		merged.settings, merged.format = ours.settings, ours.format
+	}
+	if mergeLines("the Obsidian archive", base.obsidianArchive, ours.obsidianArchive, theirs.obsidianArchive, &conflicts) {
+		merged.obsidianArchive = ours.obsidianArchive
+	}
+	merged.taskList = ours.taskList || theirs.taskList
+
+	var locations [3][][]taskLocation
+	var boardParts [3][]part
+	for version, d := range versions {
+		for _, board := range d.boards {
+			boardParts[version] = append(boardParts[version], newBoardPart(board))
+			var boardLocations []taskLocation
+			for _, list := range board.lists {
+				// lists that are not in the merged data keep where they were
+				boardLocations = append(boardLocations, taskLocation{board.boardName, list.listTitle})
+			}
+			locations[version] = append(locations[version], boardLocations)
+		}
+	}
+	boardName := func(title string) string {
+		if len(title) == 0 {
+			return "the board"
+		}
+		return fmt.Sprintf("board %q", title)
+	}
+	mergedBoards, boardConflicts := mergeParts(boardParts, boardName, "the order of the boards")
+	conflicts = append(conflicts, boardConflicts...)
+	merged.boards = nil
+	for _, mb := range mergedBoards {
+		board := Board{boardName: mb.title, extraLines: versions[mb.from].boards[mb.idx[mb.from]].extraLines}
+		var listParts [3][]part
+		for version, d := range versions {
+			if mb.idx[version] >= 0 {
+				for _, list := range d.boards[mb.idx[version]].lists {
+					listParts[version] = append(listParts[version], newListPart(list))
+				}
+			}
+		}
+		listName := func(title string) string {
+			if len(mb.title) == 0 {
+				return fmt.Sprintf("list %q", title)
+			}
+			return fmt.Sprintf("list %q of board %q", title, mb.title)
+		}
+		mergedLists, listConflicts := mergeParts(listParts, listName, "the order of the lists of "+boardName(mb.title))
+		conflicts = append(conflicts, listConflicts...)
+		for _, ml := range mergedLists {
+			source := versions[ml.from].boards[mb.idx[ml.from]].lists[ml.idx[ml.from]]
+			board.lists = append(board.lists, List{listTitle: ml.title, extraLines: source.extraLines, complete: source.complete})
+			for version := range versions {
+				if mb.idx[version] >= 0 && ml.idx[version] >= 0 {
+					locations[version][mb.idx[version]][ml.idx[version]] = taskLocation{mb.title, ml.title}
+				}
+			}
+		}
+		merged.boards = append(merged.boards, board)
+	}
+	return &merged, locations, conflicts
+}
+
+// merges the boards of a file or the lists of a board, given as parts of
+// every version. name gives the name of one in conflicts, orderName the
+// name of their order.
+func mergeParts(versions [3][]part, name func(title string) string, orderName string) ([]mergedPart, []MergeConflict) {
+	base, ours, theirs := versions[baseVersion], versions[ourVersion], versions[theirVersion]
+	ourMatches := matchParts(base, ours)
+	theirMatches := matchParts(base, theirs)
+	var parts []mergedPart
+	for baseIdx := range base {
+		parts = append(parts, mergedPart{idx: [3]int{baseIdx, slices.Index(ourMatches, baseIdx), slices.Index(theirMatches, baseIdx)}})
+	}
+	for ourIdx, baseIdx := range ourMatches {
+		if baseIdx < 0 {
+			parts = append(parts, mergedPart{idx: [3]int{-1, ourIdx, -1}})
+		}
+	}
+	for theirIdx, baseIdx := range theirMatches {
+		if baseIdx >= 0 {
+			continue
+		}
+		// added on both sides with the same title
+		added := slices.IndexFunc(parts, func(p mergedPart) bool {
+			return p.idx[baseVersion] < 0 && p.idx[theirVersion] < 0 && ours[p.idx[ourVersion]].title == theirs[theirIdx].title
+		})
+		if added >= 0 {
+			parts[added].idx[theirVersion] = theirIdx
+		} else {
+			parts = append(parts, mergedPart{idx: [3]int{-1, -1, theirIdx}})
+		}
+	}
+
+	var conflicts []MergeConflict
+	addConflict := func(title, ourChange, theirChange string) {
+		conflicts = append(conflicts, MergeConflict{Structure: name(title), OursChange: ourChange, TheirsChange: theirChange})
+	}
+	dropped := make([]bool, len(parts))
+	for partIdx := range parts {
+		p := &parts[partIdx]
+		b, o, t := p.idx[baseVersion], p.idx[ourVersion], p.idx[theirVersion]
+		switch {
+		case b >= 0 && o < 0 && t < 0:
+			// removed on both sides
+			dropped[partIdx] = true
+		case b >= 0 && o < 0:
+			dropped[partIdx] = theirs[t].content == base[b].content
+			if !dropped[partIdx] {
+				addConflict(base[b].title, "removed it", "changed it")
+			}
+			p.title, p.from = theirs[t].title, theirVersion
+		case b >= 0 && t < 0:
+			dropped[partIdx] = ours[o].content == base[b].content
+			if !dropped[partIdx] {
+				addConflict(base[b].title, "changed it", "removed it")
+			}
+			p.title, p.from = ours[o].title, ourVersion
+		case o >= 0 && t >= 0 && b < 0:
+			p.title, p.from = ours[o].title, ourVersion
+			if ours[o].body != theirs[t].body {
+				addConflict(p.title, "added it", "added it with other notes")
+			}
+		case b < 0 && o >= 0:
+			p.title, p.from = ours[o].title, ourVersion
+		case b < 0:
+			p.title, p.from = theirs[t].title, theirVersion
+		default:
+			useOurTitle, titleConflict := pickChange(ours[o].title != base[b].title, theirs[t].title != base[b].title, ours[o].title == theirs[t].title)
+			p.title = theirs[t].title
+			if useOurTitle || titleConflict {
+				p.title = ours[o].title
+			}
+			if titleConflict {
+				addConflict(base[b].title, fmt.Sprintf("renamed it to %q", ours[o].title), fmt.Sprintf("renamed it to %q", theirs[t].title))
+			}
+			useOurBody, bodyConflict := pickChange(ours[o].body != base[b].body, theirs[t].body != base[b].body, ours[o].body == theirs[t].body)
+			p.from = theirVersion
+			if useOurBody || bodyConflict {
+				p.from = ourVersion
+			}
+			if bodyConflict {
+				addConflict(p.title, "changed its notes", "changed its notes")
+			}
+		}
+	}
+
+	// the parts in the order of every version, by index in parts
+	var orders [3][]int
+	for version := range versions {
+		for idx := range versions[version] {
+			orders[version] = append(orders[version], slices.IndexFunc(parts, func(p mergedPart) bool { return p.idx[version] == idx }))
+		}
+	}
+	primary, secondary, orderConflict := mergeOrder(orders[baseVersion], orders[ourVersion], orders[theirVersion])
+	if orderConflict {
+		conflicts = append(conflicts, MergeConflict{Structure: orderName, OursChange: "changed it", TheirsChange: "changed it"})
+		primary, secondary = orders[ourVersion], orders[theirVersion]
+	}
+	var merged []mergedPart
+	for _, partIdx := range interleave(primary, secondary, func(partIdx int) bool { return !dropped[partIdx] }) {
+		p := parts[partIdx]
+		for _, other := range merged {
+			if strings.EqualFold(other.title, p.title) {
+				addConflict(p.title, "gave one of them the title", "gave another one the title")
+			}
+		}
+		merged = append(merged, p)
+	}
+	return merged, conflicts
+}
+
+// returns for every part of a version the index of the part of base it is a
+// version of, -1 for the ones added
+func matchParts(base, parts []part) []int {
+	matches := make([]int, len(parts))
+	used := make([]bool, len(base))
+	match := func(idx, baseIdx int) {
+		matches[idx] = baseIdx
+		used[baseIdx] = true
+	}
+	for idx, p := range parts {
+		matches[idx] = -1
+		for baseIdx, b := range base {
+			if !used[baseIdx] && b.title == p.title {
+				match(idx, baseIdx)
+				break
+			}
+		}
+	}
+	// renamed, with tasks
+	for idx, p := range parts {
+		if matches[idx] >= 0 {
+			continue
+		}
+		best, bestShared := -1, 0
+		for baseIdx, b := range base {
+			shared := 0
+			for _, id := range p.taskIDs {
+				if slices.Contains(b.taskIDs, id) {
+					shared++
+				}
+			}
+			if !used[baseIdx] && shared > bestShared {
+				best, bestShared = baseIdx, shared
+			}
+		}
+		if best >= 0 {
+			match(idx, best)
+		}
+	}
+	// renamed, at the same place between the same neighbours
+	for idx := range parts {
+		if matches[idx] >= 0 {
+			continue
+		}
+		baseIdx := 0
+		if idx > 0 {
+			baseIdx = matches[idx-1] + 1
+		}
+		if idx > 0 && matches[idx-1] < 0 || baseIdx >= len(base) || used[baseIdx] {
+			continue
+		}
+		nextMatches := idx+1 == len(parts) && baseIdx+1 == len(base) ||
+			idx+1 < len(parts) && matches[idx+1] == baseIdx+1
+		if nextMatches {
+			match(idx, baseIdx)
+		}
+	}
+	return matches
+}
+
+// merges a block of lines changed as a whole, e.g. the front matter. reports
+// whether to take the lines of ours, on a conflict too.
+func mergeLines(name string, base, ours, theirs []string, conflicts *[]MergeConflict) bool {
+	useOurs, conflict := pickChange(!slices.Equal(ours, base), !slices.Equal(theirs, base), slices.Equal(ours, theirs))
+	if conflict {
+		*conflicts = append(*conflicts, MergeConflict{Structure: name, OursChange: "changed it", TheirsChange: "changed it"})
+	}
+	return useOurs || conflict
+}
+
+func newBoardPart(board Board) part {
+	p := part{title: board.boardName, body: strings.Join(board.extraLines, "\n")}
+	content := []string{board.boardName, p.body}
+	for _, list := range board.lists {
+		listPart := newListPart(list)
+		p.taskIDs = append(p.taskIDs, listPart.taskIDs...)
+		content = append(content, listPart.content)
+	}
+	p.content = strings.Join(content, "\n")
+	return p
+}
+
+func newListPart(list List) part {
+	body := list.extraLines
+	if list.complete {
+		body = slices.Concat([]string{obsidianCompleteMarker}, body)
+	}
+	p := part{title: list.listTitle, body: strings.Join(body, "\n")}
+	for _, item := range list.listItems {
+		p.taskIDs = append(p.taskIDs, item.ID)
+	}
+	p.content = strings.Join(append(list.Lines(), body...), "\n")
+	return p
+}
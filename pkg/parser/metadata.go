@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// layout used for the @due(...) token
+const DueDateLayout = "2006-01-02"
+
+// how the day a task was done is written: @done(2026-10-18)
+const doneTokenFormat = "@done(%s)"
+
+// how a due date is written: @due(2026-11-01) in seiban files,
+// @{2026-11-01} in files of the Obsidian Kanban plugin
+const (
+	dueTokenFormat         = "@due(%s)"
+	obsidianDueTokenFormat = "@{%s}"
+)
+
+// represents the priority of a task, written as !low, !medium or !high
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = map[Priority]string{
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+}
+
+// returns the name of the priority, empty for PriorityNone
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+// returns the names of all the priorities, starting with PriorityNone
+func PriorityNames() []string {
+	return []string{"none", "low", "medium", "high"}
+}
+
+// parses the name of a priority (without the leading "!")
+func ParsePriority(name string) (Priority, bool) {
+	switch strings.ToLower(name) {
+	case "low":
+		return PriorityLow, true
+	case "medium", "med":
+		return PriorityMedium, true
+	case "high":
+		return PriorityHigh, true
+	}
+	return PriorityNone, false
+}
+
+// removes the GitHub task list checkbox ("[ ] " or "[x] ") from the text of a "- " line,
+// reports whether the box is ticked and whether there was a checkbox at all.
+func parseCheckbox(text string) (rest string, done, found bool) {
+	for _, checkbox := range []string{"[ ]", "[x]", "[X]"} {
+		if text == checkbox {
+			return "", checkbox != "[ ]", true
+		}
+		if strings.HasPrefix(text, checkbox+" ") {
+			return text[len(checkbox)+1:], checkbox != "[ ]", true
+		}
+	}
+	return text, false, false
+}
+
+// reports whether a trimmed "- " line is a task list item
+func isCheckboxLine(line string) bool {
+	_, _, found := parseCheckbox(strings.TrimPrefix(line, "- "))
+	return found
+}
+
+// marks the task as done or not done, a task that becomes done is dated today
+func (i *ListItem) setDone(done bool) {
+	switch {
+	case done && !i.Done:
+		i.DoneDate = today()
+	case !done:
+		i.DoneDate = time.Time{}
+	}
+	i.Done = done
+}
+
+// returns the current day, without the time
+func today() time.Time {
+	year, month, day := time.Now().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// returns the checkbox written in front of the item in a task list
+func (i ListItem) checkbox() string {
+	if i.Done {
+		return "[x] "
+	}
+	return "[ ] "
+}
+
+// splits the text of a "- " line into the task name and its metadata.
+// metadata tokens (@due(...) or @{...}, !priority, #tag) are only read from
+// the end of the line, so "fix #bug in login" keeps its name. the first
+// word is always part of the name, and a last word of the name that looks
+// like metadata is written escaped with a "\", see itemLine.
+func parseItemLine(text string) ListItem {
+	var item ListItem
+	text, item.ID = parseTaskID(text)
+	tokens := strings.Split(text, " ")
+	nameEnd := len(tokens)
+	for nameEnd > 1 && isMetadataToken(tokens[nameEnd-1]) {
+		nameEnd--
+	}
+	for _, token := range tokens[nameEnd:] {
+		item.parseMetadataToken(token)
+	}
+	nameParts := tokens[:nameEnd]
+	if last := nameParts[len(nameParts)-1]; len(nameParts) > 1 && strings.HasPrefix(last, "\\") && isMetadataToken(last[1:]) {
+		nameParts[len(nameParts)-1] = last[1:]
+	}
+	item.ItemName = strings.Join(nameParts, " ")
+	return item
+}
+
+// reports whether token is read as metadata at the end of a "- " line
+func isMetadataToken(token string) bool {
+	var item ListItem
+	return item.parseMetadataToken(token)
+}
+
+// stores the metadata held by token in the item,
+// returns false if the token is not metadata.
+func (i *ListItem) parseMetadataToken(token string) bool {
+	switch {
+	case strings.HasPrefix(token, "@done(") && strings.HasSuffix(token, ")"):
+		doneDate, err := time.Parse(DueDateLayout, token[len("@done("):len(token)-1])
+		if err != nil {
+			return false
+		}
+		i.DoneDate = doneDate
+	case strings.HasPrefix(token, "@due(") && strings.HasSuffix(token, ")"):
+		dueDate, err := time.Parse(DueDateLayout, token[len("@due("):len(token)-1])
+		if err != nil {
+			return false
+		}
+		i.DueDate = dueDate
+	case strings.HasPrefix(token, "@{") && strings.HasSuffix(token, "}"):
+		dueDate, err := time.Parse(DueDateLayout, token[len("@{"):len(token)-1])
+		if err != nil {
+			return false
+		}
+		i.DueDate = dueDate
+	case strings.HasPrefix(token, "!"):
+		priority, ok := ParsePriority(token[1:])
+		if !ok {
+			return false
+		}
+		i.Priority = priority
+	case isTag(token):
+		i.Tags = append(i.Tags, token[1:])
+	default:
+		return false
+	}
+	return true
+}
+
+// tags start with a letter so that references like #123 stay in the name
+func isTag(token string) bool {
+	if len(token) < 2 || token[0] != '#' {
+		return false
+	}
+	for idx, r := range token[1:] {
+		if idx == 0 && !unicode.IsLetter(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+// returns the metadata of the item in the form it is written to the file
+func (i ListItem) Metadata() []string {
+	return i.metadata(dueTokenFormat)
+}
+
+func (i ListItem) metadata(dueFormat string) []string {
+	var metadata []string
+	if !i.DueDate.IsZero() {
+		metadata = append(metadata, fmt.Sprintf(dueFormat, i.DueDate.Format(DueDateLayout)))
+	}
+	if !i.DoneDate.IsZero() {
+		metadata = append(metadata, fmt.Sprintf(doneTokenFormat, i.DoneDate.Format(DueDateLayout)))
+	}
+	if i.Priority != PriorityNone {
+		metadata = append(metadata, "!"+i.Priority.String())
+	}
+	for _, tag := range i.Tags {
+		metadata = append(metadata, "#"+tag)
+	}
+	return metadata
+}
+
+// returns the text of the "- " line of the item, name followed by metadata and id
+func (i ListItem) itemLine(dueFormat string) string {
+	name := i.ItemName
+	// the last word would be read as metadata, see parseItemLine
+	if lastIdx := strings.LastIndex(name, " "); lastIdx >= 0 && isMetadataToken(name[lastIdx+1:]) {
+		name = name[:lastIdx+1] + "\\" + name[lastIdx+1:]
+	}
+	parts := append([]string{name}, i.metadata(dueFormat)...)
+	if idComment := i.idComment(); len(idComment) > 0 {
+		parts = append(parts, idComment)
+	}
+	return strings.Join(parts, " ")
+}
+
+// parses a whitespace separated list of tags, the leading "#" is optional
+func ParseTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Fields(text) {
+		tag = strings.TrimPrefix(tag, "#")
+		if isTag("#" + tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
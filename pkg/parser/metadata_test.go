@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseItemLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want ListItem
+		// the line the item is written back as, if it is not line
+		saved string
+	}{
+		{"no metadata", "fix login", ListItem{ItemName: "fix login"}, ""},
+		{
+			"all the metadata",
+			"fix login @due(2026-11-01) !high #bug #auth",
+			ListItem{ItemName: "fix login", DueDate: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), Priority: PriorityHigh, Tags: []string{"bug", "auth"}},
+			"",
+		},
+		{"short priority name", "deploy !med", ListItem{ItemName: "deploy", Priority: PriorityMedium}, "deploy !medium"},
+		{"invalid date", "deploy @due(tomorrow)", ListItem{ItemName: "deploy @due(tomorrow)"}, ""},
+		{"unknown priority", "deploy !urgent", ListItem{ItemName: "deploy !urgent"}, ""},
+		{"issue reference", "fix #123", ListItem{ItemName: "fix #123"}, ""},
+		{"tag in the name", "fix #bug in login", ListItem{ItemName: "fix #bug in login"}, ""},
+		{"tag in the name and at the end", "fix #bug in login !low #auth", ListItem{ItemName: "fix #bug in login", Priority: PriorityLow, Tags: []string{"auth"}}, ""},
+		{"only a tag", "#bug", ListItem{ItemName: "#bug"}, ""},
+		{"only tags", "#bug #auth", ListItem{ItemName: "#bug", Tags: []string{"auth"}}, ""},
+		{"name ending like a tag", "release \\#v2 #ops", ListItem{ItemName: "release #v2", Tags: []string{"ops"}}, ""},
+		{"obsidian due date", "deploy @{2026-11-01}", ListItem{ItemName: "deploy", DueDate: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}, "deploy @due(2026-11-01)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseItemLine(test.line)
+			if got.ItemName != test.want.ItemName || got.Priority != test.want.Priority ||
+				!got.DueDate.Equal(test.want.DueDate) || !slices.Equal(got.Tags, test.want.Tags) {
+				t.Errorf("parseItemLine(%q) = %+v, want %+v", test.line, got, test.want)
+			}
+			saved := test.saved
+			if len(saved) == 0 {
+				saved = test.line
+			}
+			if line := got.itemLine(dueTokenFormat); line != saved {
+				t.Errorf("itemLine() = %q, want %q", line, saved)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	if got, want := ParseTags("#bug ops 123 #"), []string{"bug", "ops"}; !slices.Equal(got, want) {
+		t.Errorf("ParseTags() = %q, want %q", got, want)
+	}
+}
+
+func TestMetadataInNameRoundTrip(t *testing.T) {
+	file := "# b\n## TODO\n- [ ] fix #bug in login #auth <!-- id:a1 -->\n- [ ] #bug <!-- id:b2 -->\n- [ ] release \\#v2 <!-- id:c3 -->\n"
+	d, saved := saveText(t, file)
+	for id, want := range map[string]string{"a1": "fix #bug in login", "b2": "#bug", "c3": "release #v2"} {
+		if task, _ := d.GetTaskByID(id); task.ItemName != want {
+			t.Errorf("task %v is named %q, want %q", id, task.ItemName, want)
+		}
+	}
+	d, _ = saveText(t, strings.Join(saved, "\n"))
+	if task, _ := d.GetTaskByID("a1"); task.ItemName != "fix #bug in login" || !slices.Equal(task.Tags, []string{"auth"}) {
+		t.Errorf("saved and read again as %+v", task)
+	}
+	if task, _ := d.GetTaskByID("c3"); task.ItemName != "release #v2" || len(task.Tags) > 0 {
+		t.Errorf("saved and read again as %+v", task)
+	}
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// the dialect of markdown a board file is written in
+type Format int
+
+const (
+	// the format of seiban: "# " board, "## " lists, "\t- " tasks and "\t\t> " descriptions
+	FormatSeiban Format = iota
+	// the format of the Obsidian Kanban plugin: "kanban-plugin" front matter, "## " lanes,
+	// "- [ ] " cards, "**Complete**" markers and a "%% kanban:settings" block
+	FormatObsidian
+)
+
+const (
+	obsidianFrontMatterKey = "kanban-plugin"
+	obsidianCompleteMarker = "**Complete**"
+	obsidianSettingsStart  = "%% kanban:settings"
+	// line above the "## Archive" section of the archived cards
+	obsidianArchiveSeparator = "***"
+)
+
+// lines written for a board converted to the Obsidian format
+var (
+	obsidianFrontMatter = []string{"---", "", obsidianFrontMatterKey + ": basic", "", "---"}
+	obsidianSettings    = []string{obsidianSettingsStart, "```", `{"kanban-plugin":"basic"}`, "```", "%%"}
+)
+
+// returns the format the file is written in
+func (d *Data) GetFormat() Format {
+	return d.format
+}
+
+// changes the format the file is saved in. an Obsidian file holds one
+// board, so only the active board is kept when converting to it.
+func (d *Data) SetFormat(format Format) {
+	if format == d.format {
+		return
+	}
+	doneListIdx := d.GetDoneListIdx()
+	switch format {
+	case FormatObsidian:
+		d.boards = []Board{*d.board()}
+		d.boardIdx = 0
+		for listIdx := range d.board().lists {
+			d.board().lists[listIdx].complete = listIdx == doneListIdx
+		}
+		d.frontMatter = obsidianFrontMatter
+		d.settings = obsidianSettings
+		d.taskList = true
+	case FormatSeiban:
+		d.frontMatter = nil
+		if doneListIdx >= 0 {
+			d.frontMatter = []string{frontMatterDelimiter, "done: " + d.board().lists[doneListIdx].listTitle, frontMatterDelimiter}
+		}
+		d.settings = nil
+		d.obsidianArchive = nil
+	}
+	d.format = format
+}
+
+// returns the name of a board read from an Obsidian file, which has no "# " line
+func boardNameFromFile(fileName string) string {
+	return strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+}
+
+// removes one level of indentation (a tab or up to four spaces) from a line
+func trimIndentLevel(rawLine string) string {
+	if strings.HasPrefix(rawLine, "\t") {
+		return rawLine[1:]
+	}
+	for idx := 0; idx < 4; idx++ {
+		if !strings.HasPrefix(rawLine, " ") {
+			break
+		}
+		rawLine = rawLine[1:]
+	}
+	return rawLine
+}
+
+// keeps the archived cards of an Obsidian file, the lines from the "***"
+// line up to the settings block, as they are, and reads the settings block
+func (d *Data) parseObsidianArchive(lines []string) {
+	archiveEnd := len(lines)
+	for lineIdx, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), obsidianSettingsStart) {
+			d.settings = lines[lineIdx:]
+			archiveEnd = lineIdx
+			break
+		}
+	}
+	for archiveEnd > 0 && len(strings.TrimSpace(lines[archiveEnd-1])) == 0 {
+		archiveEnd--
+	}
+	d.obsidianArchive = lines[:archiveEnd]
+}
+
+// finishes reading an Obsidian file: the board is named after the file
+// and the lane marked as complete becomes the done list
+func (d *Data) finishObsidianParse() {
+	board := d.board()
+	if len(board.boardName) == 0 {
+		board.boardName = boardNameFromFile(d.fileName)
+	}
+	for _, list := range board.lists {
+		if list.complete && len(d.config.DoneList) == 0 {
+			d.config.DoneList = list.listTitle
+		}
+	}
+	d.taskList = true
+}
+
+// returns the lines of the file in the format of the Obsidian Kanban plugin
+func (d *Data) obsidianContent() []string {
+	var fileContent []string
+	fileContent = append(fileContent, d.frontMatter...)
+	fileContent = append(fileContent, "")
+	if len(d.preamble) > 0 {
+		fileContent = append(fileContent, d.preamble...)
+		fileContent = append(fileContent, "")
+	}
+	board := d.board()
+	if len(board.extraLines) > 0 {
+		fileContent = append(fileContent, board.extraLines...)
+		fileContent = append(fileContent, "")
+	}
+	for _, list := range board.lists {
+		fileContent = append(fileContent, "## "+list.listTitle, "")
+		if list.complete {
+			fileContent = append(fileContent, obsidianCompleteMarker)
+		}
+		fileContent = append(fileContent, list.extraLines...)
+		for _, listItem := range list.listItems {
+			fileContent = append(fileContent, d.withConflictMarkers(listItem, ListItem.obsidianLines)...)
+		}
+		fileContent = append(fileContent, "", "")
+	}
+	if len(d.obsidianArchive) > 0 {
+		fileContent = append(fileContent, d.obsidianArchive...)
+		fileContent = append(fileContent, "")
+	}
+	if len(d.settings) > 0 {
+		fileContent = append(fileContent, "", "")
+		fileContent = append(fileContent, d.settings...)
+	}
+	return fileContent
+}
+
+// returns the lines of a card in the file
+func (i ListItem) obsidianLines() []string {
+	var description, subtasks []string
+	if len(i.ItemDescription) > 0 {
+		for _, line := range strings.Split(i.ItemDescription, "\n") {
+			description = append(description, "\t"+line)
+		}
+	}
+	for _, subtask := range i.Subtasks {
+		subtasks = append(subtasks, "\t- "+subtask.line())
+	}
+	return append([]string{"- " + i.checkbox() + i.itemLine(obsidianDueTokenFormat)}, i.bodyLines(description, subtasks)...)
+}
@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const obsidianBoard = "---\n\nkanban-plugin: basic\n\n---\n\n## Todo\n\n- [ ] card one <!-- id:a1 -->\n\tmore text\n- [ ] card two <!-- id:b2 -->\n\n\n## Done\n\n**Complete**\n- [x] finished <!-- id:c3 -->\n\n\n\n\n%% kanban:settings\n```\n{\"kanban-plugin\":\"basic\"}\n```\n%%"
+
+func TestObsidianRoundTrip(t *testing.T) {
+	d := &Data{}
+	d.SetFileName("/notes/Project.md")
+	if err := d.ParseData(strings.Split(obsidianBoard, "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if d.GetFormat() != FormatObsidian {
+		t.Errorf("format = %v, want FormatObsidian", d.GetFormat())
+	}
+	if d.GetBoardName() != "Project" {
+		t.Errorf("board name = %q, want the name of the file", d.GetBoardName())
+	}
+	if d.GetDoneListIdx() != 1 {
+		t.Errorf("done list = %v, want the **Complete** one", d.GetDoneListIdx())
+	}
+	if task, _ := d.GetTaskByID("a1"); task.ItemDescription != "more text" {
+		t.Errorf("description = %q", task.ItemDescription)
+	}
+	if got := strings.Join(d.Content(), "\n"); got != obsidianBoard {
+		t.Errorf("saved as\n%q\nwant\n%q", got, obsidianBoard)
+	}
+}
+
+// a board as the Obsidian Kanban plugin writes it, with archived cards
+const obsidianArchivedBoard = "---\n\nkanban-plugin: basic\n\n---\n\n## Todo\n\n- [ ] card one <!-- id:a1 -->\n\n\n## Done\n\n**Complete**\n- [x] finished <!-- id:c3 -->\n\n\n***\n\n## Archive\n\n- [x] shipped @{2026-10-01}\n- [x] released\n\tnotes\n\n\n\n%% kanban:settings\n```\n{\"kanban-plugin\":\"basic\"}\n```\n%%"
+
+func TestObsidianArchive(t *testing.T) {
+	d := &Data{}
+	d.SetFileName("/notes/Project.md")
+	if err := d.ParseData(strings.Split(obsidianArchivedBoard, "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if names := d.GetListNames(); len(names) != 2 {
+		t.Errorf("lists = %q, the archive is not a list", names)
+	}
+	if task, _ := d.GetTaskByID("c3"); task.ItemName != "finished" || len(task.notes()) > 0 {
+		t.Errorf("last card = %+v, want it without the archive", task)
+	}
+	if got := strings.Join(d.Content(), "\n"); got != obsidianArchivedBoard {
+		t.Errorf("saved as\n%q\nwant\n%q", got, obsidianArchivedBoard)
+	}
+	if len(d.Warnings()) > 0 {
+		t.Errorf("warnings = %v", d.Warnings())
+	}
+}
@@ -2,96 +2,332 @@ package parser
 
 import (
 	"fmt"
-	"log"
 	"strings"
-
-	"github.com/ppriyankuu/seiban/pkg/files"
+	"time"
 )
 
-// represents the board name and a slice of list
+// represents the boards of a file
 type Data struct {
-	boardName string
-	lists     []List
-	fileName  string
+	boards []Board
+	// index of the board that the list and task methods work on
+	boardIdx int
+	fileName string
+	// set when the tasks are written as GitHub task list items ("- [ ] ")
+	taskList bool
+	// lines before the first "# " line that are not part of the board format
+	preamble []string
+	// lines of the front matter block, including the "---" delimiters
+	frontMatter []string
+	config      Config
+	// dialect the file is written in, and for Obsidian files the
+	// "%% kanban:settings" block at the end of the file and the archived
+	// cards above it, from the "***" line on, kept as they are
+	format          Format
+	settings        []string
+	obsidianArchive []string
+	// set when another seiban holds the lock of the file, Save then refuses to write
+	readOnly bool
+	// where the boards are loaded from and saved to
+	storage Storage
+	// the boards as they were last loaded or saved, see markSaved
+	savedContent []string
+	// hash of the stored boards as they were last loaded or saved, see Storage.Hash
+	storedHash string
+	// ids given to the tasks that had none in the file, see assignTaskIDs
+	assignedIDs map[string]bool
+	// conflicts of a merge by git, by task id, see MergeFiles
+	conflicts map[string]MergeConflict
+	// lines of the file that were kept as they are but look like a mistake
+	warnings ParseErrors
 }
 
 // represents the title of list and a list of items inside it.
 type List struct {
-	listTitle string
-	listItems []ListItem
+	listTitle  string
+	listItems  []ListItem
+	extraLines []string
+	// set for the lane of an Obsidian file marked "**Complete**"
+	complete bool
 }
 
-// represents the name of item and it's description
+// represents the name of item, it's description and metadata
 type ListItem struct {
+	// short id of the task that stays the same when the task is moved or edited
+	ID              string
 	ItemName        string
 	ItemDescription string
+	Priority        Priority
+	DueDate         time.Time
+	Tags            []string
+	Done            bool
+	Subtasks        []Subtask
+	// day the task was marked as done, written as @done(2026-10-18)
+	DoneDate time.Time
+	// the lines under the task in the order they were written, see bodyLine
+	body []bodyLine
+}
+
+// makes Save refuse to write the file
+func (d *Data) SetReadOnly(readOnly bool) {
+	d.readOnly = readOnly
 }
 
+func (d *Data) IsReadOnly() bool {
+	return d.readOnly
+}
+
+// sets the file the boards are kept in, a markdown file unless its name ends in ".json"
 func (d *Data) SetFileName(fileName string) {
 	d.fileName = fileName
+	d.storage = storageFor(fileName)
 }
 
-func (d *Data) GetContentFromFile() []string {
-	if !files.CheckFile(d.fileName) {
-		files.CreateFile(d.fileName)
-	}
-	return files.OpenFile(d.fileName)
+// returns the name of the file the boards are read from and saved to
+func (d *Data) GetFileName() string {
+	return d.fileName
 }
 
-// parses the contents of the file to custom type Data
+// parses the contents of the file to custom type Data.
+// lines that are not part of the board format (notes, comments, links, ...)
+// are kept with the nearest board, list or task and written back by Save.
+// so every file can be parsed, the lines that look like a mistake are kept
+// too and reported by Warnings.
 func (d *Data) ParseData(fileContent []string) error {
-	for lineNumber, line := range fileContent {
-		line = strings.TrimSpace(line)
+	defer d.markSaved()
+	d.warnings = nil
+	fileContent = d.parseFrontMatter(fileContent)
+	// number of lines before fileContent
+	lineOffset := len(d.frontMatter)
+	// set while the lines of a description are being read,
+	// so that consecutive "> " lines are joined into one description.
+	inDescription := false
+	// indentation of the last task line, deeper "- [ ] " lines are its subtasks.
+	// -1 when there is no task to attach subtasks to.
+	taskIndent := -1
+	// closing line of the code block or comment being read, everything up to it is kept as it is
+	rawBlockEnd := ""
+	// count of empty lines since the last non-empty line
+	emptyLines := 0
+	for lineNumber, rawLine := range fileContent {
+		line := strings.TrimSpace(rawLine)
+		if d.format == FormatObsidian && strings.HasPrefix(line, obsidianSettingsStart) {
+			d.settings = fileContent[lineNumber:]
+			break
+		}
+		if d.format == FormatObsidian && len(rawBlockEnd) == 0 && rawLine == obsidianArchiveSeparator {
+			d.parseObsidianArchive(fileContent[lineNumber:])
+			break
+		}
+		if len(rawBlockEnd) > 0 {
+			d.addExtraLine(rawLine, 0)
+			if strings.HasSuffix(line, rawBlockEnd) {
+				rawBlockEnd = ""
+			}
+			continue
+		}
 		// skipping empty lines
 		if len(line) < 1 {
+			if d.format == FormatObsidian && inDescription && indentWidth(rawLine) > taskIndent {
+				// an empty line inside the description of a card
+				currentList := &d.board().lists[d.GetListCount()-1]
+				currentList.listItems[len(currentList.listItems)-1].ItemDescription += "\n"
+				continue
+			}
+			emptyLines++
 			continue
 		}
-		if strings.HasPrefix(line, "# ") {
+		prevEmptyLines := emptyLines
+		emptyLines = 0
+		listCount := 0
+		if len(d.boards) > 0 {
+			listCount = d.GetListCount()
+		}
+		indent := indentWidth(rawLine)
+		if strings.HasPrefix(line, "# ") && d.format != FormatObsidian {
 			boardNameStartingIndex := strings.Index(line, " ") + 1
 			boardName := line[boardNameStartingIndex:]
-			d.boardName = boardName
+			d.boards = append(d.boards, Board{
+				boardName: boardName,
+			})
+			// the board being read is the active one until the whole file is read
+			d.boardIdx = len(d.boards) - 1
+			taskIndent = -1
 		} else if strings.HasPrefix(line, "## ") {
 			listNameStartIndex := strings.Index(line, " ") + 1
 			listTitle := line[listNameStartIndex:]
-			d.lists = append(d.lists, List{
+			board := d.board()
+			board.lists = append(board.lists, List{
 				listTitle: listTitle,
 			})
-		} else if strings.HasPrefix(line, "- ") {
-			listCount := d.GetListCount()
-			if listCount < 1 {
-				return fmt.Errorf("Error at line %v of file %v\n Line: %v", lineNumber, d.fileName, line)
-			}
-			currentList := d.lists[listCount-1]
+			taskIndent = -1
+		} else if strings.HasPrefix(line, "- ") && listCount > 0 && (taskIndent < 0 || indent <= taskIndent || isCheckboxLine(line)) {
+			currentList := &d.board().lists[listCount-1]
 			itemNameStartIndex := strings.Index(line, " ") + 1
-			itemName := line[itemNameStartIndex:]
-			currentList.listItems = append(currentList.listItems, ListItem{
-				ItemName: itemName,
-			})
-			d.lists[listCount-1] = currentList
-		} else if strings.HasPrefix(line, "> ") {
-			listCount := d.GetListCount()
-			if listCount < 1 {
-				return fmt.Errorf("Error at line %v of file %v\n Line: %v", lineNumber, d.fileName, line)
+			itemLine, done, isTaskListItem := parseCheckbox(line[itemNameStartIndex:])
+			if isTaskListItem && taskIndent >= 0 && indent > taskIndent {
+				lastItem := &currentList.listItems[len(currentList.listItems)-1]
+				lastItem.Subtasks = append(lastItem.Subtasks, Subtask{
+					Name: itemLine,
+					Done: done,
+				})
+				lastItem.body = append(lastItem.body, bodyLine{kind: subtaskLine})
+				continue
+			}
+			if isTaskListItem {
+				d.taskList = true
+			}
+			listItem := parseItemLine(itemLine)
+			listItem.Done = done
+			currentList.listItems = append(currentList.listItems, listItem)
+			inDescription = false
+			taskIndent = indent
+		} else if d.format == FormatObsidian && line == obsidianCompleteMarker && listCount > 0 {
+			d.board().lists[listCount-1].complete = true
+		} else if d.format == FormatObsidian && taskIndent >= 0 && indent > taskIndent {
+			// the lines of a card after its first line are its description
+			currentList := &d.board().lists[listCount-1]
+			lastItem := &currentList.listItems[len(currentList.listItems)-1]
+			itemDesc := trimIndentLevel(rawLine)
+			if inDescription {
+				lastItem.ItemDescription += "\n" + itemDesc
+			} else {
+				lastItem.ItemDescription = itemDesc
 			}
-			currentList := d.lists[listCount-1]
-			itemDescStartIndex := strings.Index(line, " ") + 1
-			itemDesc := line[itemDescStartIndex:]
-			listItemLen := len(currentList.listItems)
-			if listItemLen < 1 {
-				return fmt.Errorf("Error at line %v of file %v\n Line: %v", lineNumber, d.fileName, line)
+			lastItem.body = append(lastItem.body, bodyLine{kind: descriptionLine})
+			inDescription = true
+		} else if (line == ">" || strings.HasPrefix(line, "> ")) && taskIndent >= 0 {
+			currentList := &d.board().lists[listCount-1]
+			itemDesc := parseDescriptionLine(rawLine)
+			lastItem := &currentList.listItems[len(currentList.listItems)-1]
+			if inDescription {
+				lastItem.ItemDescription += "\n" + itemDesc
+			} else {
+				lastItem.ItemDescription = itemDesc
 			}
-			currentList.listItems[listItemLen-1].ItemDescription = itemDesc
-			d.lists[listCount-1] = currentList
+			lastItem.body = append(lastItem.body, bodyLine{kind: descriptionLine})
+			inDescription = true
 		} else {
-			return fmt.Errorf("Error at line %v of file %v\n Line: %v", lineNumber, d.fileName, line)
+			if message, hint := d.misplacedLine(line, listCount, taskIndent); len(message) > 0 {
+				d.warnings = append(d.warnings, newParseWarning(lineOffset+lineNumber, rawLine, message, hint))
+			}
+			d.addExtraLine(rawLine, prevEmptyLines)
+			rawBlockEnd = rawBlockEndOf(line)
 		}
 	}
+	d.boardIdx = 0
+	if d.format == FormatObsidian {
+		d.finishObsidianParse()
+	}
+	d.assignTaskIDs()
+	d.finishWarnings()
 	return nil
 }
 
-// returns the name of board
+// returns why a line of a board that is not part of the board format looks
+// like a mistake, empty if it is a note. tasks and descriptions belong in a
+// list, the line is kept as a note either way.
+func (d *Data) misplacedLine(line string, listCount, taskIndent int) (message, hint string) {
+	if d.format != FormatSeiban || len(d.boards) == 0 {
+		return "", ""
+	}
+	switch {
+	case strings.HasPrefix(line, "- ") && listCount == 0:
+		return "task outside of any list", "add a \"## \" list heading above it"
+	case (line == ">" || strings.HasPrefix(line, "> ")) && taskIndent < 0:
+		return "description outside of any task", "put it under a \"- \" task line"
+	}
+	return "", ""
+}
+
+// keeps a line that is not part of the board format with the node it belongs to:
+// the last task, the last list, the last board, or the file (before the first "# " line).
+// the empty lines before it are kept too, unless it is the first extra line of the node.
+func (d *Data) addExtraLine(rawLine string, emptyLines int) {
+	var extraLines *[]string
+	if len(d.boards) == 0 {
+		extraLines = &d.preamble
+	} else {
+		board := d.board()
+		listCount := len(board.lists)
+		switch {
+		case listCount > 0 && len(board.lists[listCount-1].listItems) > 0:
+			list := &board.lists[listCount-1]
+			list.listItems[len(list.listItems)-1].addExtraLine(rawLine, emptyLines)
+			return
+		case listCount > 0:
+			extraLines = &board.lists[listCount-1].extraLines
+		default:
+			extraLines = &board.extraLines
+		}
+	}
+	if len(*extraLines) > 0 {
+		for range emptyLines {
+			*extraLines = append(*extraLines, "")
+		}
+	}
+	*extraLines = append(*extraLines, rawLine)
+}
+
+// returns the line that closes the code block or html comment opened by line,
+// empty if line does not open one (or closes it right away).
+func rawBlockEndOf(line string) string {
+	switch {
+	case strings.HasPrefix(line, "```"):
+		return "```"
+	case strings.HasPrefix(line, "~~~"):
+		return "~~~"
+	case strings.HasPrefix(line, "<!--") && !strings.Contains(line, "-->"):
+		return "-->"
+	}
+	return ""
+}
+
+// returns the width of the indentation of a line, counting a tab as four spaces
+func indentWidth(rawLine string) int {
+	width := 0
+	for _, r := range rawLine {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// returns the text of a "> " line, only the indentation and the quote
+// marker are removed so that the description is kept as it was written.
+func parseDescriptionLine(rawLine string) string {
+	line := strings.TrimLeft(rawLine, " \t")
+	line = strings.TrimPrefix(line, ">")
+	return strings.TrimPrefix(line, " ")
+}
+
+// returns the "> " lines a description is written as, one per line of the description
+func descriptionLines(description string) []string {
+	var lines []string
+	for _, line := range strings.Split(description, "\n") {
+		if len(line) == 0 {
+			lines = append(lines, "\t\t>")
+			continue
+		}
+		lines = append(lines, "\t\t> "+line)
+	}
+	return lines
+}
+
+// returns the lines of the file that were kept as notes but look like a
+// mistake, e.g. a task outside of any list
+func (d *Data) Warnings() ParseErrors {
+	return d.warnings
+}
+
+// returns the name of the active board
 func (d *Data) GetBoardName() string {
-	return d.boardName
+	return d.board().boardName
 }
 
 // returns the list based on index
@@ -100,13 +336,13 @@ func (d *Data) GetList(listIdx int) (*List, error) {
 	if err := checkBounds(listIdx, listCount); err != nil {
 		return nil, err
 	}
-	return &d.lists[listIdx], nil
+	return &d.board().lists[listIdx], nil
 }
 
 // returns a list of all the list names
 func (d *Data) GetListNames() []string {
 	var listNames []string
-	for _, list := range d.lists {
+	for _, list := range d.board().lists {
 		listNames = append(listNames, list.listTitle)
 	}
 	return listNames
@@ -128,6 +364,17 @@ func (d *Data) GetTask(listIdx, taskIdx int) (*ListItem, error) {
 	return &list.listItems[taskIdx], nil
 }
 
+// returns a copy of all the items of a particular list
+func (d *Data) GetListItems(listIdx int) ([]ListItem, error) {
+	list, err := d.GetList(listIdx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ListItem, len(list.listItems))
+	copy(items, list.listItems)
+	return items, nil
+}
+
 // returns a list of all the tasks of a particular list
 func (d *Data) GetTasks(listIdx int) ([]string, error) {
 	list, err := d.GetList(listIdx)
@@ -141,51 +388,52 @@ func (d *Data) GetTasks(listIdx int) ([]string, error) {
 	return tasks, nil
 }
 
-// adds a new task to a list provided the list index, task (title, description), and task index.
-func (d *Data) AddNewTask(listIdx int, taskTitle, taskDesc string, taskIdx int) error {
+// adds a new task to a list provided the list index, task, and task index.
+func (d *Data) AddNewTask(listIdx int, newTask ListItem, taskIdx int) error {
 	if err := checkBounds(listIdx, d.GetListCount()); err != nil {
 		return err
 	}
-	newTask := ListItem{
-		ItemName:        taskTitle,
-		ItemDescription: taskDesc,
+	if len(newTask.ID) == 0 {
+		newTask.ID = d.NewTaskID()
 	}
 	err := d.insertTask(listIdx, newTask, taskIdx)
 	if err != nil {
 		return err
 	}
-	d.Save()
 	return nil
 }
 
-// edits a task
-func (d *Data) EditTask(listIdx, taskIdx int, taskTitle, taskDesc string) error {
+// edits a task, replacing its title, description and metadata. a done task
+// keeps the day it was done, the one of editedTask if it has one.
+func (d *Data) EditTask(listIdx, taskIdx int, editedTask ListItem) error {
 	task, err := d.GetTask(listIdx, taskIdx)
 	if err != nil {
 		return err
 	}
-	task.ItemName = taskTitle
-	task.ItemDescription = taskDesc
+	done, doneDate := editedTask.Done, editedTask.DoneDate
+	editedTask.Done, editedTask.DoneDate = task.Done, task.DoneDate
+	*task = editedTask
+	task.setDone(done)
+	if done && !doneDate.IsZero() {
+		task.DoneDate = doneDate
+	}
 	return nil
 }
 
+// moves a task to the end of another list
 func (d *Data) MoveTask(taskIdx, sourceListIdx, destListIdx int) error {
-	sourceTask, err := d.GetTask(sourceListIdx, taskIdx)
-	if err != nil {
+	if _, err := d.GetTask(sourceListIdx, taskIdx); err != nil {
 		return err
 	}
-	newListTaskCount, err := d.GetTaskCount(destListIdx)
+	destList, err := d.GetList(destListIdx)
 	if err != nil {
 		return err
 	}
-	taskTitle := sourceTask.ItemName
-	taskDesc := sourceTask.ItemDescription
-	err = d.AddNewTask(destListIdx, taskTitle, taskDesc, newListTaskCount)
-	if err != nil {
-		return nil
-	}
-	_, err = d.RemoveTask(sourceListIdx, taskIdx)
-	return err
+	sourceList := &d.board().lists[sourceListIdx]
+	task := sourceList.listItems[taskIdx]
+	sourceList.listItems = append(sourceList.listItems[:taskIdx], sourceList.listItems[taskIdx+1:]...)
+	destList.listItems = append(destList.listItems, task)
+	return nil
 }
 
 // removes a task given the index of list and the task.
@@ -200,7 +448,6 @@ func (d *Data) RemoveTask(listIdx, taskIdx int) (ListItem, error) {
 	}
 	taskData := *task
 	list.listItems = append(list.listItems[:taskIdx], list.listItems[taskIdx+1:]...)
-	d.Save()
 	return taskData, nil
 }
 
@@ -209,6 +456,9 @@ func (d *Data) insertTask(listIdx int, task ListItem, taskIdx int) error {
 	if err != nil {
 		return err
 	}
+	if err := checkBounds(taskIdx, len(list.listItems)+1); err != nil {
+		return err
+	}
 	if len(list.listItems) < 1 {
 		list.listItems = append(list.listItems, task)
 		return nil
@@ -220,26 +470,120 @@ func (d *Data) insertTask(listIdx int, task ListItem, taskIdx int) error {
 	return nil
 }
 
-func (d *Data) Save() {
-	if d.fileName == "" {
-		return
+// writes the boards to the storage, a file is replaced only once all of it is
+// written. the methods that change the boards do not save them, the caller
+// saves once it made all of its changes, see SaveMerged.
+func (d *Data) Save() error {
+	if d.storage == nil {
+		return nil
+	}
+	if d.readOnly {
+		return fmt.Errorf("Cannot save file %v: it is opened read-only", d.fileName)
+	}
+	hash, err := d.storage.Save(d)
+	if err != nil {
+		return fmt.Errorf("Cannot save file %v: %v", d.fileName, err)
+	}
+	// backups are a safety net, a board that cannot be backed up is still saved
+	d.backup(d.savedContent)
+	d.markSaved()
+	d.storedHash = hash
+	return nil
+}
+
+// returns the lines of the file, in the format the file was read in
+func (d *Data) Content() []string {
+	if d.format == FormatObsidian {
+		return d.obsidianContent()
+	}
+	if !d.taskList {
+		// once a task is marked as done the file switches to task list items
+		d.taskList = d.hasDoneTask()
 	}
 	var fileContent []string
-	fileContent = append(fileContent, "# "+d.boardName+"\n")
-	for _, list := range d.lists {
-		fileContent = append(fileContent, "## "+list.listTitle)
-		for _, listItem := range list.listItems {
-			fileContent = append(fileContent, "\t- "+listItem.ItemName)
-			if len(listItem.ItemDescription) > 0 {
-				fileContent = append(fileContent, "\t\t> "+listItem.ItemDescription)
+	if len(d.frontMatter) > 0 {
+		fileContent = append(fileContent, d.frontMatter...)
+		fileContent = append(fileContent, "")
+	}
+	if len(d.preamble) > 0 {
+		fileContent = append(fileContent, d.preamble...)
+		fileContent = append(fileContent, "")
+	}
+	// a file without a "# " line is saved as one board without a name
+	d.board()
+	for _, board := range d.boards {
+		fileContent = append(fileContent, "# "+board.boardName)
+		fileContent = append(fileContent, board.extraLines...)
+		fileContent = append(fileContent, "")
+		for _, list := range board.lists {
+			fileContent = append(fileContent, "## "+list.listTitle)
+			fileContent = append(fileContent, list.extraLines...)
+			for _, listItem := range list.listItems {
+				fileContent = append(fileContent, d.withConflictMarkers(listItem, func(item ListItem) []string {
+					return item.lines(d.taskList)
+				})...)
 			}
+			fileContent = append(fileContent, "\n")
 		}
-		fileContent = append(fileContent, "\n")
 	}
-	err := files.WriteFile(fileContent, d.fileName)
+	return fileContent
+}
+
+// returns the lines of a task in the file, with a checkbox in front of it for task lists
+func (i ListItem) lines(taskList bool) []string {
+	itemLine := i.itemLine(dueTokenFormat)
+	if taskList {
+		itemLine = i.checkbox() + itemLine
+	}
+	var description, subtasks []string
+	if len(i.ItemDescription) > 0 {
+		description = descriptionLines(i.ItemDescription)
+	}
+	for _, subtask := range i.Subtasks {
+		subtasks = append(subtasks, "\t\t- "+subtask.line())
+	}
+	return append([]string{"\t- " + itemLine}, i.bodyLines(description, subtasks)...)
+}
+
+// returns the lines of the task as they are written in a seiban file, see ParseTask
+func (i ListItem) Lines() []string {
+	return i.lines(true)
+}
+
+// parses the lines of one task, as returned by ListItem.Lines
+func ParseTask(lines []string) (ListItem, error) {
+	d := &Data{}
+	if err := d.ParseData(append([]string{"# task", "## task"}, lines...)); err != nil {
+		return ListItem{}, err
+	}
+	listItems := d.board().lists[0].listItems
+	if len(d.boards) != 1 || len(d.board().lists) != 1 || len(listItems) != 1 {
+		return ListItem{}, fmt.Errorf("Cannot parse task: %q is not one task", lines)
+	}
+	return listItems[0], nil
+}
+
+// marks a task as done or not done
+func (d *Data) SetTaskDone(listIdx, taskIdx int, done bool) error {
+	task, err := d.GetTask(listIdx, taskIdx)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	task.setDone(done)
+	return nil
+}
+
+func (d *Data) hasDoneTask() bool {
+	for _, board := range d.boards {
+		for _, list := range board.lists {
+			for _, listItem := range list.listItems {
+				if listItem.Done {
+					return true
+				}
+			}
+		}
 	}
+	return false
 }
 
 // swaps a task of one list with another list
@@ -253,13 +597,12 @@ func (d *Data) SwapListItems(listIdx, firstTaskIdx, secondTaskIdx int) error {
 		return err
 	}
 	*firstTask, *secondTask = *secondTask, *firstTask
-	d.Save()
 	return nil
 }
 
 // returns the count of lists
 func (d *Data) GetListCount() int {
-	return len(d.lists)
+	return len(d.board().lists)
 }
 
 // gives the task count of a particular task
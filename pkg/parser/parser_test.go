@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+// parses the text of a board file, saves it and returns the lines written
+func saveText(t *testing.T, text string) (*Data, []string) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	d := &Data{}
+	if err := d.ParseData(strings.Split(text, "\n")); err != nil {
+		t.Fatalf("ParseData: %v", err)
+	}
+	d.SetFileName("seiban.md")
+	if err := d.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return d, files.OpenFile("seiban.md")
+}
+
+func TestDescriptions(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"one line", "# b\n## TODO\n- one\n> first line\n", "first line"},
+		{"several lines", "# b\n## TODO\n- one\n\t\t> first line\n\t\t> second line\n", "first line\nsecond line"},
+		{"empty lines", "# b\n## TODO\n- one\n> first line\n>\n> third line\n", "first line\n\nthird line"},
+		{"indentation kept", "# b\n## TODO\n- one\n>   indented\n", "  indented"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, saved := saveText(t, test.file)
+			task, err := d.GetTask(0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if task.ItemDescription != test.want {
+				t.Errorf("description = %q, want %q", task.ItemDescription, test.want)
+			}
+			reloaded := &Data{}
+			if err := reloaded.ParseData(saved); err != nil {
+				t.Fatalf("ParseData of the saved file: %v", err)
+			}
+			if task, _ := reloaded.GetTask(0, 0); task.ItemDescription != test.want {
+				t.Errorf("description read back = %q, want %q\n%v", task.ItemDescription, test.want, strings.Join(saved, "\n"))
+			}
+		})
+	}
+}
+
+func TestCheckboxes(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		done []bool
+		// the "- " lines of the saved file
+		want []string
+	}{
+		{"plain list", "# b\n## TODO\n- one <!-- id:a1 -->\n- two <!-- id:b2 -->\n", []bool{false, false}, []string{"\t- one <!-- id:a1 -->", "\t- two <!-- id:b2 -->"}},
+		{"task list", "# b\n## TODO\n- [ ] one <!-- id:a1 -->\n- [x] two <!-- id:b2 -->\n- [X] three <!-- id:c3 -->\n", []bool{false, true, true}, []string{"\t- [ ] one <!-- id:a1 -->", "\t- [x] two <!-- id:b2 -->", "\t- [x] three <!-- id:c3 -->"}},
+		{"some tasks with a checkbox", "# b\n## TODO\n- one <!-- id:a1 -->\n- [x] two <!-- id:b2 -->\n", []bool{false, true}, []string{"\t- [ ] one <!-- id:a1 -->", "\t- [x] two <!-- id:b2 -->"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, saved := saveText(t, test.file)
+			for taskIdx, done := range test.done {
+				if task, _ := d.GetTask(0, taskIdx); task.Done != done {
+					t.Errorf("task %v done = %v, want %v", taskIdx, task.Done, done)
+				}
+			}
+			var lines []string
+			for _, line := range saved {
+				if strings.HasPrefix(line, "\t- ") {
+					lines = append(lines, line)
+				}
+			}
+			if strings.Join(lines, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("saved as %q, want %q", lines, test.want)
+			}
+		})
+	}
+}
+
+func TestSetTaskDoneAddsCheckboxes(t *testing.T) {
+	d, _ := saveText(t, "# b\n## TODO\n- one <!-- id:a1 -->\n- two <!-- id:b2 -->\n")
+	if err := d.SetTaskDone(0, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	saved := strings.Join(files.OpenFile("seiban.md"), "\n")
+	done := "@done(" + time.Now().Format("2006-01-02") + ")"
+	if !strings.Contains(saved, "\t- [ ] one <!-- id:a1 -->\n\t- [x] two "+done+" <!-- id:b2 -->") {
+		t.Errorf("saved as\n%v", saved)
+	}
+}
+
+func TestExtraLines(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		// the number of tasks of the first list
+		tasks int
+	}{
+		{"notes", "notes on top\n# b\nboard note\n## TODO\nlist note\n\t- one <!-- id:a1 -->\n\t\ttask note\n", 1},
+		{"links", "# b\n## TODO\n\t- one <!-- id:a1 -->\n\t\t[design](https://example.com/design)\n", 1},
+		{"code block", "# b\n## TODO\n\t- one <!-- id:a1 -->\n```\n- not a task\n# not a board\n```\n", 1},
+		{"html comment", "# b\n## TODO\n\t- one <!-- id:a1 -->\n<!--\n## not a list\n- not a task\n-->\n", 1},
+		{"between description and subtasks", "# b\n## TODO\n\t- [ ] one <!-- id:a1 -->\n\t\t> a\n  a note\n\t\t> b\n\t\t- a plain bullet\n\t\t- [ ] sub\n  [a link](https://example.com)\n", 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, saved := saveText(t, test.file)
+			if d.GetListCount() != 1 {
+				t.Errorf("lists = %q, want one", d.GetListNames())
+			}
+			if count, _ := d.GetTaskCount(0); count != test.tasks {
+				t.Errorf("task count = %v, want %v", count, test.tasks)
+			}
+			// the lines are written back in the same order
+			var got []string
+			for _, line := range saved {
+				if line = strings.TrimSpace(line); len(line) > 0 {
+					got = append(got, line)
+				}
+			}
+			var want []string
+			for _, line := range strings.Split(test.file, "\n") {
+				if line = strings.TrimSpace(line); len(line) > 0 {
+					want = append(want, line)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("saved as\n%v\nwant\n%v", strings.Join(saved, "\n"), test.file)
+			}
+		})
+	}
+}
+
+func TestExtraLinesInTaskBody(t *testing.T) {
+	d, _ := saveText(t, "# b\n## TODO\n\t- [ ] one <!-- id:a1 -->\n\t\t> a\n  a note\n\t\t> b\n\t\t- a plain bullet\n\t\t- [ ] sub\n")
+	task, _ := d.GetTaskByID("a1")
+	if task.ItemDescription != "a\nb" || len(task.Subtasks) != 1 {
+		t.Errorf("task = %+v", task)
+	}
+	if notes := task.notes(); len(notes) != 2 {
+		t.Errorf("notes = %q, want the note and the plain bullet", notes)
+	}
+}
+
+func TestSaveError(t *testing.T) {
+	d, _ := saveText(t, "# b\n## TODO\n- one <!-- id:a1 -->\n")
+	d.SetFileName("missing/seiban.md")
+	if err := d.AddNewTask(0, ListItem{ItemName: "two"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Save(); err == nil {
+		t.Error("a change that cannot be saved succeeded")
+	}
+}
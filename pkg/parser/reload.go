@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"slices"
+)
+
+// remembers the boards as they were last loaded or saved, to notice changes
+// that are not saved yet
+func (d *Data) markSaved() {
+	d.savedContent = d.Content()
+}
+
+// reports whether the boards changed since they were last loaded or saved
+func (d *Data) HasUnsavedChanges() bool {
+	return !slices.Equal(d.Content(), d.savedContent)
+}
+
+// reports whether the stored boards were changed by another program since
+// they were last loaded or saved. the stored bytes are compared, not the
+// boards read from them, which can differ from read to read (e.g. tasks
+// without an id get a new one each time).
+func (d *Data) FileChanged() (bool, error) {
+	if d.storage == nil {
+		return false, nil
+	}
+	hash, err := d.storage.Hash()
+	if err != nil {
+		return false, err
+	}
+	return hash != d.storedHash, nil
+}
+
+// returns the boards as they are in the storage now
+func (d *Data) loadStored() (*Data, error) {
+	stored := &Data{fileName: d.fileName, readOnly: d.readOnly, storage: d.storage}
+	if err := stored.Load(); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// loads the boards again, dropping the changes that are not saved. the board
+// with the name of the active board stays active. the data is left as it
+// is if the boards cannot be loaded.
+func (d *Data) Reload() error {
+	reloaded, err := d.loadStored()
+	if err != nil {
+		return err
+	}
+	reloaded.selectBoard(d.GetBoardName())
+	*d = *reloaded
+	return nil
+}
+
+// makes the board of the given name active, if there is one
+func (d *Data) selectBoard(boardName string) {
+	for boardIdx, name := range d.GetBoardNames() {
+		if name == boardName {
+			d.boardIdx = boardIdx
+			return
+		}
+	}
+}
+
+// saves the boards. if the stored boards were changed by another program
+// since they were last loaded or saved, the changes are merged first, see
+// MergeFile. tasks changed on both sides are returned and the boards are not
+// saved, they are saved by the next call once ResolveConflict picked a
+// version of every one of them.
+func (d *Data) SaveMerged() ([]MergeConflict, error) {
+	// a file that cannot be read (e.g. it was removed) is written again
+	if changed, err := d.FileChanged(); err == nil && changed {
+		conflicts, err := d.MergeFile()
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			return conflicts, nil
+		}
+	}
+	return nil, d.Save()
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"os"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	d, _ := saveText(t, twoBoards)
+	if err := d.SetActiveBoard(1); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := d.FileChanged(); err != nil || changed {
+		t.Fatalf("FileChanged() = %v, %v right after Save", changed, err)
+	}
+	// another program swaps the boards and adds a task
+	if err := os.WriteFile("seiban.md", []byte("# home\n## TODO\n- two <!-- id:b2 -->\n- four <!-- id:d4 -->\n# work\n## TODO\n- one <!-- id:a1 -->\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := d.FileChanged(); err != nil || !changed {
+		t.Fatalf("FileChanged() = %v, %v after another program changed it", changed, err)
+	}
+	if err := d.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if d.GetBoardName() != "home" {
+		t.Errorf("active board = %q, want home", d.GetBoardName())
+	}
+	if _, err := d.GetTaskByID("d4"); err != nil {
+		t.Error(err)
+	}
+	if changed, err := d.FileChanged(); err != nil || changed {
+		t.Errorf("FileChanged() = %v, %v right after Reload", changed, err)
+	}
+}
+
+func TestHasUnsavedChanges(t *testing.T) {
+	d, _ := saveText(t, twoBoards)
+	if d.HasUnsavedChanges() {
+		t.Error("HasUnsavedChanges() right after Save")
+	}
+	// changed in memory
+	task, _ := d.GetTask(0, 0)
+	task.Done = true
+	if !d.HasUnsavedChanges() {
+		t.Error("a task marked as done is not an unsaved change")
+	}
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if d.HasUnsavedChanges() {
+		t.Error("HasUnsavedChanges() after Save")
+	}
+}
+
+func TestReloadKeepsDataOnError(t *testing.T) {
+	d, _ := saveText(t, twoBoards)
+	// a directory cannot be read as a board file
+	if err := os.Remove("seiban.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir("seiban.md", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Reload(); err == nil {
+		t.Fatal("Reload() of a file that cannot be read succeeded")
+	}
+	if d.GetBoardCount() != 2 {
+		t.Errorf("boards = %q, want them as they were", d.GetBoardNames())
+	}
+}
+
+func TestFileChanged(t *testing.T) {
+	// tasks without an id get a new one every time the file is read
+	d, storage := loadText(t, "# b\n\n## TODO\n- one\n- two\n")
+	if changed, err := d.FileChanged(); err != nil || changed {
+		t.Fatalf("FileChanged() = %v, %v right after Load", changed, err)
+	}
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := d.FileChanged(); err != nil || changed {
+		t.Fatalf("FileChanged() = %v, %v right after Save", changed, err)
+	}
+	storage.SetContent([]string{"# b", "", "## TODO", "- three"})
+	if changed, err := d.FileChanged(); err != nil || !changed {
+		t.Fatalf("FileChanged() = %v, %v after another program changed it", changed, err)
+	}
+}
@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ppriyankuu/seiban/pkg/files"
+)
+
+// where the boards of a Data are kept
+type Storage interface {
+	// reads the stored boards into d, which is empty. returns the hash of the
+	// stored boards that were read, see Hash.
+	Load(d *Data) (hash string, err error)
+	// stores the boards of d, returns the hash of what was stored
+	Save(d *Data) (hash string, err error)
+	// returns a hash of the stored boards as they are stored (e.g. of the
+	// bytes of the file), which changes when another program changes them
+	Hash() (string, error)
+	// calls onChange, from another goroutine, when the stored boards may have
+	// changed, until stop is called. saves of d itself may be reported too.
+	Watch(onChange func()) (stop func(), err error)
+}
+
+// sets where the boards are loaded from and saved to
+func (d *Data) SetStorage(storage Storage) {
+	d.storage = storage
+}
+
+// reads the boards from the storage
+func (d *Data) Load() error {
+	if d.storage == nil {
+		return nil
+	}
+	hash, err := d.storage.Load(d)
+	if err != nil {
+		return err
+	}
+	d.markSaved()
+	d.storedHash = hash
+	return nil
+}
+
+// calls onChange, from another goroutine, when the stored boards may have changed
+func (d *Data) Watch(onChange func()) (stop func(), err error) {
+	if d.storage == nil {
+		return func() {}, nil
+	}
+	return d.storage.Watch(onChange)
+}
+
+// returns the storage for a board file, picked by the extension of its name
+func storageFor(fileName string) Storage {
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		return NewJSONStorage(fileName)
+	}
+	return NewMarkdownStorage(fileName)
+}
+
+// keeps the boards in a markdown file, the default storage
+type MarkdownStorage struct {
+	fileName string
+}
+
+func NewMarkdownStorage(fileName string) *MarkdownStorage {
+	return &MarkdownStorage{fileName: fileName}
+}
+
+// reads the file, it is created if it does not exist
+func (s *MarkdownStorage) Load(d *Data) (string, error) {
+	if !files.CheckFile(s.fileName) {
+		files.CreateFile(s.fileName)
+	}
+	fileContent, hash, err := files.ReadFileWithHash(s.fileName)
+	if err != nil {
+		return "", err
+	}
+	return hash, d.ParseData(fileContent)
+}
+
+// replaces the file, see files.WriteFile
+func (s *MarkdownStorage) Save(d *Data) (string, error) {
+	fileContent := d.Content()
+	if err := files.WriteFile(fileContent, s.fileName); err != nil {
+		return "", err
+	}
+	return files.HashLines(fileContent), nil
+}
+
+// returns the hash of the bytes of the file
+func (s *MarkdownStorage) Hash() (string, error) {
+	return files.HashFile(s.fileName)
+}
+
+func (s *MarkdownStorage) Watch(onChange func()) (func(), error) {
+	return watchFile(s.fileName, onChange)
+}
+
+func watchFile(fileName string, onChange func()) (func(), error) {
+	watcher, err := files.WatchFile(fileName, onChange)
+	if err != nil {
+		return nil, err
+	}
+	return watcher.Close, nil
+}
+
+// keeps the boards in memory as the lines of a markdown board file,
+// for tools and tests that use boards without a file
+type MemoryStorage struct {
+	mu       sync.Mutex
+	content  []string
+	watchers map[int]func()
+	// key of the next watcher
+	nextWatcher int
+}
+
+// creates a storage that holds the given lines of a markdown board file
+func NewMemoryStorage(content []string) *MemoryStorage {
+	return &MemoryStorage{content: slices.Clone(content), watchers: make(map[int]func())}
+}
+
+func (s *MemoryStorage) Load(d *Data) (string, error) {
+	content := s.Content()
+	return files.HashLines(content), d.ParseData(content)
+}
+
+func (s *MemoryStorage) Save(d *Data) (string, error) {
+	// lines of Content may hold several lines of the file
+	content := strings.Split(strings.Join(d.Content(), "\n"), "\n")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.content = content
+	return files.HashLines(content), nil
+}
+
+// returns the hash of the stored lines
+func (s *MemoryStorage) Hash() (string, error) {
+	return files.HashLines(s.Content()), nil
+}
+
+// calls onChange when SetContent changes the stored lines
+func (s *MemoryStorage) Watch(onChange func()) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := s.nextWatcher
+	s.nextWatcher++
+	s.watchers[key] = onChange
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, key)
+	}, nil
+}
+
+// returns the stored lines
+func (s *MemoryStorage) Content() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.content)
+}
+
+// replaces the stored lines, as another program changing a board file would
+func (s *MemoryStorage) SetContent(content []string) {
+	s.mu.Lock()
+	s.content = slices.Clone(content)
+	var watchers []func()
+	for _, onChange := range s.watchers {
+		watchers = append(watchers, onChange)
+	}
+	s.mu.Unlock()
+	for _, onChange := range watchers {
+		go onChange()
+	}
+}
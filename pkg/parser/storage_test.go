@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loads the text of a board file from a MemoryStorage
+func loadText(t *testing.T, text string) (*Data, *MemoryStorage) {
+	t.Helper()
+	storage := NewMemoryStorage(strings.Split(text, "\n"))
+	d := &Data{}
+	d.SetStorage(storage)
+	if err := d.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return d, storage
+}
+
+func TestMemoryStorage(t *testing.T) {
+	d, storage := loadText(t, twoBoards)
+	if err := d.MoveTaskByID("a1", 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if content := strings.Join(storage.Content(), "\n"); !strings.Contains(content, "## DONE\n\t- one <!-- id:a1 -->") {
+		t.Errorf("stored as\n%v", content)
+	}
+	changed := make(chan bool, 1)
+	stop, err := d.Watch(func() { changed <- true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	storage.SetContent(strings.Split(twoBoards, "\n"))
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SetContent was not reported")
+	}
+}
+
+func TestJSONStorage(t *testing.T) {
+	file := "---\ndone: DONE\n---\n\nnotes\n\n# b\n\n## TODO\n\t- [x] one @due(2026-11-01) !high #bug <!-- id:a1 -->\n\t\t> first line\n\t\t> second line\n\t\t- [x] sub\n\n\n## DONE\n\n"
+	d, _ := loadText(t, file)
+	jsonFileName := filepath.Join(t.TempDir(), "board.json")
+	d.SetFileName(jsonFileName)
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded := &Data{}
+	loaded.SetFileName(jsonFileName)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(loaded.Content(), "\n"); got != file {
+		t.Errorf("loaded from JSON as\n%q\nwant\n%q", got, file)
+	}
+}
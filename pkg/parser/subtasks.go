@@ -0,0 +1,99 @@
+package parser
+
+import "slices"
+
+// represents an item of the checklist of a task
+type Subtask struct {
+	Name string
+	Done bool
+}
+
+// returns the text of the "- [ ] " line of the subtask
+func (s Subtask) line() string {
+	if s.Done {
+		return "[x] " + s.Name
+	}
+	return "[ ] " + s.Name
+}
+
+// returns the number of done subtasks and the total number of subtasks
+func (i ListItem) SubtaskProgress() (done, total int) {
+	for _, subtask := range i.Subtasks {
+		if subtask.Done {
+			done++
+		}
+	}
+	return done, len(i.Subtasks)
+}
+
+// returns a copy of the checklist of a task
+func (d *Data) GetSubtasks(taskID string) ([]Subtask, error) {
+	task, err := d.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(task.Subtasks), nil
+}
+
+// adds a subtask to the checklist of a task at the given position.
+// the checklist is copied before it is changed, as copies of a task
+// (kept by commands for undo) share it with the task.
+func (d *Data) AddSubtask(taskID string, subtask Subtask, subtaskIdx int) error {
+	task, err := d.GetTaskByID(taskID)
+	if err != nil {
+		return err
+	}
+	if err := checkBounds(subtaskIdx, len(task.Subtasks)+1); err != nil {
+		return err
+	}
+	task.Subtasks = slices.Insert(slices.Clone(task.Subtasks), subtaskIdx, subtask)
+	return nil
+}
+
+// removes a subtask from the checklist of a task
+func (d *Data) RemoveSubtask(taskID string, subtaskIdx int) (Subtask, error) {
+	task, err := d.GetTaskByID(taskID)
+	if err != nil {
+		return Subtask{}, err
+	}
+	if err := checkBounds(subtaskIdx, len(task.Subtasks)); err != nil {
+		return Subtask{}, err
+	}
+	subtask := task.Subtasks[subtaskIdx]
+	task.Subtasks = slices.Delete(slices.Clone(task.Subtasks), subtaskIdx, subtaskIdx+1)
+	return subtask, nil
+}
+
+// ticks or unticks a subtask
+func (d *Data) ToggleSubtask(taskID string, subtaskIdx int) error {
+	task, err := d.GetTaskByID(taskID)
+	if err != nil {
+		return err
+	}
+	if err := checkBounds(subtaskIdx, len(task.Subtasks)); err != nil {
+		return err
+	}
+	subtasks := slices.Clone(task.Subtasks)
+	subtasks[subtaskIdx].Done = !subtasks[subtaskIdx].Done
+	task.Subtasks = subtasks
+	return nil
+}
+
+// swaps two subtasks of the checklist of a task
+func (d *Data) SwapSubtasks(taskID string, firstSubtaskIdx, secondSubtaskIdx int) error {
+	task, err := d.GetTaskByID(taskID)
+	if err != nil {
+		return err
+	}
+	subtaskCount := len(task.Subtasks)
+	if err := checkBounds(firstSubtaskIdx, subtaskCount); err != nil {
+		return err
+	}
+	if err := checkBounds(secondSubtaskIdx, subtaskCount); err != nil {
+		return err
+	}
+	subtasks := slices.Clone(task.Subtasks)
+	subtasks[firstSubtaskIdx], subtasks[secondSubtaskIdx] = subtasks[secondSubtaskIdx], subtasks[firstSubtaskIdx]
+	task.Subtasks = subtasks
+	return nil
+}
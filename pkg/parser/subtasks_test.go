@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubtasks(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []Subtask
+	}{
+		{"checklist", "# b\n## TODO\n\t- [ ] release\n\t\t- [x] tag\n\t\t- [ ] announce\n", []Subtask{{Name: "tag", Done: true}, {Name: "announce"}}},
+		{"after the description", "# b\n## TODO\n- [ ] release\n\t> the big one\n\t- [ ] tag\n", []Subtask{{Name: "tag"}}},
+		{"same indentation is a task", "# b\n## TODO\n- [ ] release\n- [ ] tag\n", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, saved := saveText(t, test.file)
+			task, _ := d.GetTask(0, 0)
+			if subtasks, _ := d.GetSubtasks(task.ID); !slices.Equal(subtasks, test.want) {
+				t.Errorf("subtasks = %+v, want %+v", subtasks, test.want)
+			}
+			reloaded := &Data{}
+			if err := reloaded.ParseData(saved); err != nil {
+				t.Fatal(err)
+			}
+			if subtasks, _ := reloaded.GetSubtasks(task.ID); !slices.Equal(subtasks, test.want) {
+				t.Errorf("subtasks read back = %+v, want %+v", subtasks, test.want)
+			}
+		})
+	}
+}
+
+func TestSubtaskProgress(t *testing.T) {
+	task := ListItem{Subtasks: []Subtask{{Name: "a", Done: true}, {Name: "b"}, {Name: "c", Done: true}}}
+	if done, total := task.SubtaskProgress(); done != 2 || total != 3 {
+		t.Errorf("SubtaskProgress() = %v, %v, want 2, 3", done, total)
+	}
+}